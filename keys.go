@@ -0,0 +1,278 @@
+package main
+
+import (
+	"cmp"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// keyDef — ключ сортировки -k поле[.символ][bnr][,поле[.символ][bnr]].
+// Поля и символы нумеруются с единицы
+type keyDef struct {
+	startField, startChar int
+	endField, endChar     int // endField = 0 — до конца строки, endChar = 0 — до конца поля
+	startBlanks           bool
+	endBlanks             bool
+	numeric               bool
+	reverse               bool
+	hasOptions            bool // у ключа свои параметры, и глобальные -n и -r к нему не применяются
+}
+
+// wholeLine — ключ по умолчанию: вся строка
+var wholeLine = keyDef{startField: 1, startChar: 1}
+
+// parseKeyDef разбирает значение флага -k
+func parseKeyDef(s string) (keyDef, error) {
+	start, end, hasEnd := strings.Cut(s, ",")
+
+	var k keyDef
+	var err error
+	k.startField, k.startChar, k.startBlanks, err = k.parsePosition(start, 1)
+	if err != nil {
+		return keyDef{}, fmt.Errorf("invalid key %q: %w", s, err)
+	}
+	if k.startChar == 0 {
+		return keyDef{}, fmt.Errorf("invalid key %q: character offset is zero", s)
+	}
+	if hasEnd {
+		k.endField, k.endChar, k.endBlanks, err = k.parsePosition(end, 0)
+		if err != nil {
+			return keyDef{}, fmt.Errorf("invalid key %q: %w", s, err)
+		}
+	}
+	return k, nil
+}
+
+// parsePosition разбирает позицию поле[.символ][bnr]; символ по умолчанию — defChar.
+// Параметры n и r записываются в k
+func (k *keyDef) parsePosition(s string, defChar int) (field, char int, blanks bool, err error) {
+	num := strings.TrimRight(s, "bnr")
+	opts := s[len(num):]
+	fieldStr, charStr, hasChar := strings.Cut(num, ".")
+
+	if field, err = strconv.Atoi(fieldStr); err != nil || field < 1 {
+		return 0, 0, false, fmt.Errorf("invalid field number %q", fieldStr)
+	}
+	char = defChar
+	if hasChar {
+		if char, err = strconv.Atoi(charStr); err != nil || char < 0 {
+			return 0, 0, false, fmt.Errorf("invalid character offset %q", charStr)
+		}
+	}
+
+	for _, o := range opts {
+		switch o {
+		case 'b':
+			blanks = true
+		case 'n':
+			k.numeric = true
+		case 'r':
+			k.reverse = true
+		}
+	}
+	k.hasOptions = k.hasOptions || opts != ""
+	return field, char, blanks, nil
+}
+
+// isBlank — пробельные символы, разделяющие поля без -t
+func isBlank(b byte) bool {
+	return b == ' ' || b == '\t'
+}
+
+// fields возвращает границы полей строки. С разделителем sep поля — части строки
+// между его вхождениями. Без него поле — пробельные символы вместе со следующими
+// за ними непробельными
+func fields(line, sep string) [][2]int {
+	var res [][2]int
+	if sep != "" {
+		start := 0
+		for {
+			i := strings.Index(line[start:], sep)
+			if i < 0 {
+				return append(res, [2]int{start, len(line)})
+			}
+			res = append(res, [2]int{start, start + i})
+			start += i + len(sep)
+		}
+	}
+
+	for i := 0; i < len(line); {
+		start := i
+		for i < len(line) && isBlank(line[i]) {
+			i++
+		}
+		for i < len(line) && !isBlank(line[i]) {
+			i++
+		}
+		res = append(res, [2]int{start, i})
+	}
+	return res
+}
+
+// extract возвращает часть line, которую k сравнивает; fs — поля line.
+// Как и в sort, смещение символа может выходить за конец поля, но не строки
+func (k keyDef) extract(line string, fs [][2]int) string {
+	if k.startField > len(fs) {
+		return ""
+	}
+	f := fs[k.startField-1]
+	pos := f[0]
+	if k.startBlanks {
+		pos = skipBlanks(line, pos, f[1])
+	}
+	pos = min(pos+k.startChar-1, len(line))
+
+	end := len(line)
+	if k.endField > 0 && k.endField <= len(fs) {
+		g := fs[k.endField-1]
+		end = g[1]
+		if k.endChar > 0 {
+			e := g[0]
+			if k.endBlanks {
+				e = skipBlanks(line, e, g[1])
+			}
+			end = min(e+k.endChar, len(line))
+		}
+	}
+
+	if end < pos {
+		return ""
+	}
+	return line[pos:end]
+}
+
+func skipBlanks(line string, i, end int) int {
+	for i < end && isBlank(line[i]) {
+		i++
+	}
+	return i
+}
+
+// number — значение числа для -n: знак, целая часть без ведущих нулей
+// и дробная без хвостовых. Числа сравниваются по цифрам, поэтому точно при любой длине,
+// в том числе для целых больше 2^53, которые float64 различить не может
+type number struct {
+	neg        bool
+	whole      string
+	fractional string
+}
+
+// parseNumber возвращает значение числа в начале s, как sort -n:
+// пробелы, необязательный минус, цифры и дробная часть. Без цифр — 0
+func parseNumber(s string) number {
+	i := skipBlanks(s, 0, len(s))
+	var n number
+	if i < len(s) && s[i] == '-' {
+		n.neg = true
+		i++
+	}
+	j := i
+	for j < len(s) && isDigit(s[j]) {
+		j++
+	}
+	n.whole = strings.TrimLeft(s[i:j], "0")
+	if j < len(s) && s[j] == '.' {
+		k := j + 1
+		for k < len(s) && isDigit(s[k]) {
+			k++
+		}
+		n.fractional = strings.TrimRight(s[j+1:k], "0")
+	}
+	if n.whole == "" && n.fractional == "" {
+		n.neg = false // -0 равен 0
+	}
+	return n
+}
+
+// compareNumbers сравнивает числа a и b
+func compareNumbers(a, b number) int {
+	if a.neg != b.neg {
+		if a.neg {
+			return -1
+		}
+		return 1
+	}
+	r := cmp.Or(
+		cmp.Compare(len(a.whole), len(b.whole)),
+		strings.Compare(a.whole, b.whole),
+		strings.Compare(a.fractional, b.fractional),
+	)
+	if a.neg {
+		r = -r
+	}
+	return r
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+// key — значение ключа строки
+type key struct {
+	s   string
+	num number
+}
+
+// line — строка ввода с вычисленными ключами и номером
+type line struct {
+	text  string
+	keys  []key
+	index int
+}
+
+// newLine вычисляет ключи строки text один раз, чтобы не разбирать её при каждом сравнении
+func newLine(text string, index int, defs []keyDef, sep string) line {
+	fs := fields(text, sep)
+	keys := make([]key, len(defs))
+	for i, d := range defs {
+		keys[i].s = d.extract(text, fs)
+		if d.numeric {
+			keys[i].num = parseNumber(keys[i].s)
+		}
+	}
+	return line{text, keys, index}
+}
+
+// lineC сравнивает строки по ключам defs. Если ключи равны и lastResort,
+// строки сравниваются целиком побайтово (в обратном порядке при reverse)
+type lineC struct {
+	defs       []keyDef
+	lastResort bool
+	reverse    bool
+}
+
+// Compare упорядочивает строки с равными ключами по номеру, так что
+// результат не зависит от хода параллельной сортировки
+func (c lineC) Compare(a, b line) int {
+	if r := c.compareKeys(a, b); r != 0 {
+		return r
+	}
+	return cmp.Compare(a.index, b.index)
+}
+
+func (c lineC) compareKeys(a, b line) int {
+	for i, d := range c.defs {
+		var r int
+		if d.numeric {
+			r = compareNumbers(a.keys[i].num, b.keys[i].num)
+		} else {
+			r = strings.Compare(a.keys[i].s, b.keys[i].s)
+		}
+		if d.reverse {
+			r = -r
+		}
+		if r != 0 {
+			return r
+		}
+	}
+
+	if !c.lastResort {
+		return 0
+	}
+	r := strings.Compare(a.text, b.text)
+	if c.reverse {
+		r = -r
+	}
+	return r
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"cmp"
+	"reflect"
+	"testing"
+)
+
+func TestParseKeyDef(t *testing.T) {
+	tests := []struct {
+		key      string
+		expected keyDef
+	}{
+		{"2", keyDef{startField: 2, startChar: 1}},
+		{"2,2", keyDef{startField: 2, startChar: 1, endField: 2}},
+		{"1.3,2.4", keyDef{startField: 1, startChar: 3, endField: 2, endChar: 4}},
+		{"3n", keyDef{startField: 3, startChar: 1, numeric: true, hasOptions: true}},
+		{"3,3nr", keyDef{startField: 3, startChar: 1, endField: 3, numeric: true, reverse: true, hasOptions: true}},
+		{"2b,2b", keyDef{startField: 2, startChar: 1, endField: 2, startBlanks: true, endBlanks: true, hasOptions: true}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			k, err := parseKeyDef(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(k, tt.expected) {
+				t.Errorf("parseKeyDef(%q) = %+v, want %+v", tt.key, k, tt.expected)
+			}
+		})
+	}
+
+	for _, key := range []string{"", "0", "a", "1.0", "1.x", "-1", "1,", "1,0"} {
+		if _, err := parseKeyDef(key); err == nil {
+			t.Errorf("parseKeyDef(%q) succeeded, want error", key)
+		}
+	}
+}
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		name     string
+		line     string
+		sep      string
+		expected [][2]int
+	}{
+		{"Empty line", "", "", nil},
+		{"Blanks belong to next field", "ab  cd\tef", "", [][2]int{{0, 2}, {2, 6}, {6, 9}}},
+		{"Leading and trailing blanks", " a ", "", [][2]int{{0, 2}, {2, 3}}},
+		{"Separator", "a::b", ":", [][2]int{{0, 1}, {2, 2}, {3, 4}}},
+		{"Separator on empty line", "", ":", [][2]int{{0, 0}}},
+		{"Multibyte separator", "а→б", "→", [][2]int{{0, 2}, {5, 7}}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := fields(tt.line, tt.sep); !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("fields(%q, %q) = %v, want %v", tt.line, tt.sep, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestExtract(t *testing.T) {
+	tests := []struct {
+		line     string
+		sep      string
+		key      string
+		expected string
+	}{
+		{"alice  sales 4200", "", "2", "  sales 4200"},
+		{"alice  sales 4200", "", "2,2", "  sales"},
+		{"alice  sales 4200", "", "2b,2", "sales"},
+		{"alice  sales 4200", "", "2.2,2.4", " sa"},
+		{"alice  sales 4200", "", "2.2b,2.3b", "al"},
+		{"alice  sales 4200", "", "1.1,1.8", "alice  s"},
+		{"alice  sales 4200", "", "4", ""},
+		{"alice  sales 4200", "", "1.10,1", ""},
+		{"alice  sales 4200", "", "3,9", " 4200"},
+		{"root:x:0:0", ":", "3,3", "0"},
+		{"root:x:0:0", ":", "2.5,3", ""},
+		{"root:x:0:0", ":", "2.2,3", ":0"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.key, func(t *testing.T) {
+			k, err := parseKeyDef(tt.key)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := k.extract(tt.line, fields(tt.line, tt.sep)); got != tt.expected {
+				t.Errorf("-k %s of %q = %q, want %q", tt.key, tt.line, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseNumber(t *testing.T) {
+	tests := []struct {
+		s        string
+		expected number
+	}{
+		{"42", number{whole: "42"}},
+		{"  -3.5abc", number{neg: true, whole: "3", fractional: "5"}},
+		{"007", number{whole: "7"}},
+		{".5", number{fractional: "5"}},
+		{"5.", number{whole: "5"}},
+		{"2.50", number{whole: "2", fractional: "5"}},
+		{"-0.0", number{}},
+		{"1e3", number{whole: "1"}},
+		{"+1", number{}},
+		{"-", number{}},
+		{"abc", number{}},
+		{"", number{}},
+	}
+
+	for _, tt := range tests {
+		if got := parseNumber(tt.s); got != tt.expected {
+			t.Errorf("parseNumber(%q) = %+v, want %+v", tt.s, got, tt.expected)
+		}
+	}
+}
+
+func TestCompareNumbers(t *testing.T) {
+	// Числа перечислены по возрастанию; соседние 2^53 и 2^53+1 равны во float64
+	ordered := []string{
+		"-18446744073709551617", "-18446744073709551616", "-10", "-2.5", "-2.49", "-0.001",
+		"0", "0.001", "0.01", "1", "2.49", "2.5", "10",
+		"9007199254740992", "9007199254740993", "18446744073709551616",
+	}
+
+	for i, a := range ordered {
+		for j, b := range ordered {
+			got := compareNumbers(parseNumber(a), parseNumber(b))
+			if expected := cmp.Compare(i, j); got != expected {
+				t.Errorf("compareNumbers(%s, %s) = %d, want %d", a, b, got, expected)
+			}
+		}
+	}
+
+	if r := compareNumbers(parseNumber("-0"), parseNumber("0.000")); r != 0 {
+		t.Errorf("compareNumbers(-0, 0.000) = %d, want 0", r)
+	}
+}
@@ -1,58 +1,303 @@
+// Команда mm-qsort сортирует строки файлов или стандартного ввода, как sort(1),
+// параллельной быстрой сортировкой из пакета qsort. Строки сравниваются побайтово
+// (как sort с LC_ALL=C), а строки с равными ключами — целиком.
+//
+// Использование:
+//
+//	mm-qsort [-cnru] [-k ключ]... [-t разделитель] [-o файл] [-j n] [файл...]
+//
+// Параметры разбираются, как в getopt: короткие можно объединять (-nr), значения —
+// писать слитно (-k2, -t:), а параметры и файлы — чередовать.
+// Без файлов и для файла «-» читается стандартный ввод. Код возврата — 0 при успехе,
+// 1, если с -c ввод не упорядочен, и 2 при ошибке
 package main
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
-	"time"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"unicode/utf8"
 
-	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
 	"github.com/leonid-voroshilov/mm-qsort/pkg/qsort"
 )
 
+const progName = "mm-qsort"
+
 func main() {
-	n := 100000
-	// Тестирование с целыми числами
-	fmt.Printf("Тестирование с целыми числами: \n N = %d\n", n)
-	intData := qsort.GenerateRandomInts(n)
+	os.Exit(run(os.Args[1:], os.Stdin, os.Stdout, os.Stderr))
+}
 
-	// Копируем данные для сравнения
-	intDataCopy := make([]int, len(intData))
-	copy(intDataCopy, intData)
+// options — параметры командной строки
+type options struct {
+	check   bool
+	numeric bool
+	reverse bool
+	unique  bool
+	keys    []keyDef
+	sep     string
+	output  string
+	jobs    int
+}
 
-	intComp := comparator.IntC{}
+// optionHelp — параметры в порядке справки; у параметров со значением задан arg
+var optionHelp = []struct {
+	name       byte
+	arg, usage string
+}{
+	{'c', "", "проверить, что ввод упорядочен, вместо сортировки"},
+	{'n', "", "сравнивать числовые значения"},
+	{'r', "", "сортировать в обратном порядке"},
+	{'u', "", "выводить только первую из строк с равными ключами"},
+	{'k', "ключ", "ключ поле[.символ][bnr][,поле[.символ][bnr]]; параметр можно повторять"},
+	{'t', "разделитель", "разделитель полей; по умолчанию поля разделяют пробелы"},
+	{'o', "файл", "записать результат в файл вместо стандартного вывода"},
+	{'j', "n", "число горутин, не меньше 1; по умолчанию — число процессоров"},
+	{'h', "", "показать эту справку"},
+}
 
-	// Измеряем время параллельной сортировки
-	start := time.Now()
-	qsort.ParallelQuickSort(intData, intComp)
-	parallelTime := time.Since(start)
+// errHelp — запрошена справка -h
+var errHelp = errors.New("help requested")
+
+// usage выводит справку по параметрам
+func usage(w io.Writer) {
+	fmt.Fprintf(w, "Использование: %s [-cnru] [-k ключ]... [-t разделитель] [-o файл] [-j n] [файл...]\n", progName)
+	for _, o := range optionHelp {
+		fmt.Fprintf(w, "  -%c %s\n    \t%s\n", o.name, o.arg, o.usage)
+	}
+}
 
-	// Измеряем время последовательной сортировки
-	start = time.Now()
-	qsort.SequentialQuickSort(intDataCopy, intComp)
-	sequentialTime := time.Since(start)
+// parseArgs разбирает параметры и возвращает их вместе с именами файлов.
+// Как в getopt, короткие параметры можно объединять (-nr), значение параметра
+// можно писать слитно (-k2, -t:) или следующим аргументом (-k 2), а параметры
+// и файлы — чередовать. После «--» все аргументы считаются файлами, «-» — файл
+func parseArgs(args []string, stderr io.Writer) (options, []string, error) {
+	var o options
+	var files []string
+	fail := func(err error) (options, []string, error) {
+		fmt.Fprintf(stderr, "%s: %v\n", progName, err)
+		return options{}, nil, err
+	}
 
-	fmt.Printf("	Параллельная сортировка: %v\n", parallelTime)
-	fmt.Printf("	Последовательная сортировка: %v\n", sequentialTime)
-	fmt.Printf("	Ускорение: %.2fx\n", float64(sequentialTime)/float64(parallelTime))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--":
+			files = append(files, args[i+1:]...)
+			i = len(args)
+			continue
+		case arg == "--help":
+			usage(stderr)
+			return options{}, nil, errHelp
+		case strings.HasPrefix(arg, "--"):
+			usage(stderr)
+			return fail(fmt.Errorf("unrecognized option %q", arg))
+		case len(arg) < 2 || arg[0] != '-':
+			files = append(files, arg)
+			continue
+		}
 
-	// Проверяем корректность сортировки
-	fmt.Printf("Массив отсортирован корректно: %v\n", isSorted(intData, intComp))
+		for j := 1; j < len(arg); j++ {
+			switch name := arg[j]; name {
+			case 'c':
+				o.check = true
+			case 'n':
+				o.numeric = true
+			case 'r':
+				o.reverse = true
+			case 'u':
+				o.unique = true
+			case 'h':
+				usage(stderr)
+				return options{}, nil, errHelp
+			case 'k', 't', 'o', 'j':
+				// Значение — остаток аргумента или, если он пуст, следующий аргумент
+				value := arg[j+1:]
+				if value == "" {
+					if i+1 == len(args) {
+						usage(stderr)
+						return fail(fmt.Errorf("option requires an argument -- '%c'", name))
+					}
+					i++
+					value = args[i]
+				}
+				if err := o.set(name, value); err != nil {
+					return fail(err)
+				}
+				j = len(arg)
+			default:
+				usage(stderr)
+				return fail(fmt.Errorf("invalid option -- '%c'", name))
+			}
+		}
+	}
 
-	// Тестирование со строками
-	fmt.Println("Тестирование со строками:")
-	stringData := []string{"zebra", "apple", "banana", "cherry", "date", "elderberry", "fig", "grape"}
-	stringComp := comparator.StringC{}
+	if o.sep != "" && utf8.RuneCountInString(o.sep) != 1 {
+		return fail(fmt.Errorf("separator %q must be a single character", o.sep))
+	}
+	if o.check && len(files) > 1 {
+		return fail(fmt.Errorf("extra operand %q with -c", files[1]))
+	}
 
-	fmt.Println("	До сортировки:", stringData)
-	qsort.ParallelQuickSort(stringData, stringComp)
-	fmt.Println("	После сортировки:", stringData)
+	// Глобальные -n и -r действуют на ключи без собственных параметров
+	for i, k := range o.keys {
+		if !k.hasOptions {
+			o.keys[i].numeric, o.keys[i].reverse = o.numeric, o.reverse
+		}
+	}
+	if len(o.keys) == 0 {
+		k := wholeLine
+		k.numeric, k.reverse = o.numeric, o.reverse
+		o.keys = []keyDef{k}
+	}
+	return o, files, nil
 }
 
-// Функция для проверки корректности сортировки
-func isSorted[T any](data []T, comp comparator.Comparator[T]) bool {
-	for i := 1; i < len(data); i++ {
-		if comp.Compare(data[i-1], data[i]) > 0 {
-			return false
+// set записывает значение value параметра name
+func (o *options) set(name byte, value string) error {
+	switch name {
+	case 'k':
+		k, err := parseKeyDef(value)
+		if err != nil {
+			return err
 		}
+		o.keys = append(o.keys, k)
+	case 't':
+		o.sep = value
+	case 'o':
+		o.output = value
+	case 'j':
+		n, err := strconv.Atoi(value)
+		if err != nil || n < 1 {
+			return fmt.Errorf("invalid number of jobs %q: must be a positive integer", value)
+		}
+		o.jobs = n
+	}
+	return nil
+}
+
+// comparator возвращает сравнение строк по параметрам o. С -u строки
+// с равными ключами считаются одинаковыми и целиком не сравниваются
+func (o options) comparator() lineC {
+	return lineC{defs: o.keys, lastResort: !o.unique, reverse: o.reverse}
+}
+
+func run(args []string, stdin io.Reader, stdout, stderr io.Writer) int {
+	o, files, err := parseArgs(args, stderr)
+	if errors.Is(err, errHelp) {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+
+	texts, err := readLines(files, stdin)
+	if err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", progName, err)
+		return 2
+	}
+	lines := make([]line, len(texts))
+	for i, t := range texts {
+		lines[i] = newLine(t, i, o.keys, o.sep)
+	}
+	c := o.comparator()
+
+	if o.check {
+		if i := disorder(lines, c, o.unique); i > 0 {
+			name := "-"
+			if len(files) > 0 {
+				name = files[0]
+			}
+			fmt.Fprintf(stderr, "%s: %s:%d: disorder: %s\n", progName, name, i+1, lines[i].text)
+			return 1
+		}
+		return 0
+	}
+
+	qsort.Sort(lines, c, qsort.WithMaxGoroutines(o.jobs))
+	if o.unique {
+		lines = uniqueLines(lines, c)
+	}
+
+	if err := writeLines(o.output, stdout, lines); err != nil {
+		fmt.Fprintf(stderr, "%s: %v\n", progName, err)
+		return 2
+	}
+	return 0
+}
+
+// readLines читает строки всех файлов подряд; «-» и пустой список — стандартный ввод
+func readLines(files []string, stdin io.Reader) ([]string, error) {
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	var lines []string
+	for _, name := range files {
+		var data []byte
+		var err error
+		if name == "-" {
+			data, err = io.ReadAll(stdin)
+		} else {
+			data, err = os.ReadFile(name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if len(data) == 0 {
+			continue
+		}
+		lines = append(lines, strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")...)
+	}
+	return lines, nil
+}
+
+// disorder возвращает номер первой строки, стоящей не на своём месте, или -1.
+// При strict равные строки тоже считаются беспорядком
+func disorder(lines []line, c lineC, strict bool) int {
+	for i := 1; i < len(lines); i++ {
+		r := c.compareKeys(lines[i-1], lines[i])
+		if r > 0 || strict && r == 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// uniqueLines оставляет первую строку из каждой группы строк с равными ключами
+func uniqueLines(lines []line, c lineC) []line {
+	res := lines[:0]
+	for i, l := range lines {
+		if i == 0 || c.compareKeys(res[len(res)-1], l) != 0 {
+			res = append(res, l)
+		}
+	}
+	return res
+}
+
+// writeLines записывает строки в файл output или, если он не задан, в stdout.
+// Файл создаётся после чтения ввода, так что output может совпадать с входным файлом
+func writeLines(output string, stdout io.Writer, lines []line) (err error) {
+	if output != "" {
+		f, ferr := os.Create(output)
+		if ferr != nil {
+			return ferr
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		stdout = f
+	}
+
+	w := bufio.NewWriter(stdout)
+	for _, l := range lines {
+		w.WriteString(l.text)
+		w.WriteByte('\n')
 	}
-	return true
+	return w.Flush()
 }
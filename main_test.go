@@ -0,0 +1,190 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+var update = flag.Bool("update", false, "перезаписать эталонные файлы testdata/*.golden")
+
+// checkGolden сравнивает got с testdata/name.golden или, с флагом -update, перезаписывает его
+func checkGolden(t *testing.T, name string, got []byte) {
+	t.Helper()
+	path := filepath.Join("testdata", name+".golden")
+	if *update {
+		if err := os.WriteFile(path, got, 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(got, expected) {
+		t.Errorf("output differs from %s:\n%s", path, got)
+	}
+}
+
+func TestRunGolden(t *testing.T) {
+	// Эталоны совпадают с выводом LC_ALL=C sort с теми же флагами
+	tests := []struct {
+		name string
+		args []string
+	}{
+		{"fruits", []string{"testdata/fruits.txt"}},
+		{"fruits-reverse", []string{"-r", "testdata/fruits.txt"}},
+		{"fruits-unique", []string{"-u", "testdata/fruits.txt"}},
+		{"fruits-char-offset", []string{"-k", "1.2", "testdata/fruits.txt"}},
+		{"numbers", []string{"-n", "testdata/numbers.txt"}},
+		{"numbers-reverse", []string{"-n", "-r", "testdata/numbers.txt"}},
+		{"numbers-unique", []string{"-n", "-u", "testdata/numbers.txt"}},
+		{"passwd-uid", []string{"-t", ":", "-k", "3,3n", "testdata/passwd.txt"}},
+		{"passwd-shell-name", []string{"-t", ":", "-k", "7", "-k", "1,1", "testdata/passwd.txt"}},
+		{"passwd-gid-unique", []string{"-t", ":", "-k", "4,4n", "-u", "testdata/passwd.txt"}},
+		{"table-dept-salary", []string{"-k", "2,2", "-k", "3,3nr", "testdata/table.txt"}},
+		{"table-salary-unique", []string{"-k", "3,3n", "-u", "testdata/table.txt"}},
+		{"table-chars", []string{"-k", "2.2,2.3", "testdata/table.txt"}},
+		{"table-blanks", []string{"-k", "2b,2", "testdata/table.txt"}},
+		{"large-numeric", []string{"-k", "2nr", "-k", "1", "testdata/large.txt"}},
+		{"large-unique", []string{"-u", "-k", "1,1", "testdata/large.txt"}},
+		{"several-files", []string{"testdata/fruits.txt", "testdata/numbers.txt"}},
+		{"bignums", []string{"-n", "testdata/bignums.txt"}},
+		{"numbers-combined", []string{"-nr", "testdata/numbers.txt"}},
+		{"fruits-attached-offset", []string{"-k1.3", "testdata/fruits.txt"}},
+		{"table-attached-key", []string{"-k2", "testdata/table.txt"}},
+		{"passwd-attached-separator", []string{"-t:", "-k3,3n", "testdata/passwd.txt"}},
+		{"passwd-comma-separator", []string{"-t,", "-k2", "testdata/passwd.txt"}},
+		{"mixed-order", []string{"testdata/fruits.txt", "-ru", "testdata/numbers.txt", "-k", "1,1"}},
+		{"double-dash", []string{"-r", "--", "testdata/fruits.txt"}},
+	}
+
+	for _, tt := range tests {
+		for _, jobs := range []string{"1", "4"} {
+			t.Run(tt.name+"/j="+jobs, func(t *testing.T) {
+				var stdout, stderr bytes.Buffer
+
+				code := run(append([]string{"-j", jobs}, tt.args...), nil, &stdout, &stderr)
+
+				if code != 0 || stderr.Len() != 0 {
+					t.Fatalf("run() = %d, stderr %q", code, stderr.String())
+				}
+				checkGolden(t, tt.name, stdout.Bytes())
+			})
+		}
+	}
+}
+
+func TestRunStdin(t *testing.T) {
+	var stdout, stderr bytes.Buffer
+	stdin := strings.NewReader("b\n\na\nc")
+
+	code := run([]string{"-r", "-"}, stdin, &stdout, &stderr)
+
+	if code != 0 || stdout.String() != "c\nb\na\n\n" {
+		t.Errorf("run() = %d, stdout %q, stderr %q", code, stdout.String(), stderr.String())
+	}
+}
+
+func TestRunOutputFile(t *testing.T) {
+	// -o может указывать на входной файл: он перезаписывается после чтения
+	path := filepath.Join(t.TempDir(), "fruits.txt")
+	data, err := os.ReadFile("testdata/fruits.txt")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	var stdout, stderr bytes.Buffer
+	code := run([]string{"-o", path, path}, nil, &stdout, &stderr)
+
+	if code != 0 || stdout.Len() != 0 || stderr.Len() != 0 {
+		t.Fatalf("run() = %d, stdout %q, stderr %q", code, stdout.String(), stderr.String())
+	}
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	checkGolden(t, "fruits", got)
+}
+
+func TestRunCheck(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		code   int
+		stderr string
+	}{
+		{"Sorted", []string{"-c", "testdata/fruits.golden"}, 0, ""},
+		{"Sorted numbers", []string{"-c", "-n", "testdata/numbers.golden"}, 0, ""},
+		{"Unsorted", []string{"-c", "testdata/fruits.txt"}, 1, "mm-qsort: testdata/fruits.txt:2: disorder: apple\n"},
+		{"Duplicates with -u", []string{"-c", "-u", "testdata/fruits.golden"}, 1, "mm-qsort: testdata/fruits.golden:6: disorder: apple\n"},
+		{"Unique", []string{"-c", "-u", "testdata/fruits-unique.golden"}, 0, ""},
+		{"Reverse key", []string{"-c", "-k", "2,2", "-k", "3,3nr", "testdata/table-dept-salary.golden"}, 0, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, nil, &stdout, &stderr)
+
+			if code != tt.code || stderr.String() != tt.stderr || stdout.Len() != 0 {
+				t.Errorf("run() = %d, stdout %q, stderr %q; want %d, stderr %q",
+					code, stdout.String(), stderr.String(), tt.code, tt.stderr)
+			}
+		})
+	}
+}
+
+func TestRunHelp(t *testing.T) {
+	for _, args := range [][]string{{"-h"}, {"-nh"}, {"--help"}} {
+		var stdout, stderr bytes.Buffer
+
+		code := run(args, nil, &stdout, &stderr)
+
+		if code != 0 || !strings.HasPrefix(stderr.String(), "Использование: mm-qsort") {
+			t.Errorf("run(%q) = %d, stderr %q; want 0 and usage", args, code, stderr.String())
+		}
+	}
+}
+
+func TestRunErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		args   []string
+		stderr string
+	}{
+		{"Missing file", []string{"testdata/missing.txt"}, "no such file or directory"},
+		{"Long separator", []string{"-t", "::", "testdata/passwd.txt"}, `separator "::" must be a single character`},
+		{"Zero field", []string{"-k", "0", "testdata/fruits.txt"}, `invalid field number "0"`},
+		{"Zero offset", []string{"-k", "1.0", "testdata/fruits.txt"}, "character offset is zero"},
+		{"Unknown flag", []string{"-x"}, "invalid option -- 'x'"},
+		{"Unknown flag in group", []string{"-nx"}, "invalid option -- 'x'"},
+		{"Unknown long flag", []string{"--numeric"}, `unrecognized option "--numeric"`},
+		{"Missing value", []string{"testdata/fruits.txt", "-k"}, "option requires an argument -- 'k'"},
+		{"Invalid jobs", []string{"-jx", "testdata/fruits.txt"}, `invalid number of jobs "x"`},
+		{"Negative jobs", []string{"-j", "-2", "testdata/fruits.txt"}, `invalid number of jobs "-2"`},
+		{"Zero jobs", []string{"-j0", "testdata/fruits.txt"}, `invalid number of jobs "0"`},
+		{"Check several files", []string{"-c", "testdata/fruits.txt", "testdata/numbers.txt"}, "extra operand"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var stdout, stderr bytes.Buffer
+
+			code := run(tt.args, nil, &stdout, &stderr)
+
+			if code != 2 || !strings.Contains(stderr.String(), tt.stderr) {
+				t.Errorf("run() = %d, stderr %q; want 2 and stderr containing %q", code, stderr.String(), tt.stderr)
+			}
+		})
+	}
+}
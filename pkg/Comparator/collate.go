@@ -0,0 +1,152 @@
+package comparator
+
+import (
+	"cmp"
+	"sync"
+)
+
+// Strength — сколько уровней сравнения UCA различает CollateC
+type Strength int
+
+const (
+	// Primary различает только базовые буквы: "a" = "á" = "A"
+	Primary Strength = iota + 1
+	// Secondary различает также диакритику: "a" = "A" < "á"
+	Secondary
+	// Tertiary различает также регистр и варианты начертания: "a" < "A" < "á"
+	Tertiary
+)
+
+// CollateC — компаратор строк по алгоритму сравнения Юникода (UCA) с таблицей
+// по умолчанию DUCET 13.0.0: строки приводятся к NFD и сравниваются по уровням,
+// поэтому "Ärger" < "Zebra", а регистр важен только при равенстве букв и диакритики.
+// Переменные элементы (пробелы, пунктуация) не игнорируются.
+// Нулевое значение сравнивает на всех трёх уровнях.
+//
+// Compare не выделяет память, но каждый раз заново раскладывает обе строки,
+// так что сортировка n строк делает O(n log n) разложений. Для больших срезов
+// ключи Key выгоднее вычислить один раз: qsort.SortByKey(data, c.Key, comparator.BytesC{})
+type CollateC struct {
+	Strength      Strength // по умолчанию Tertiary
+	IgnoreCase    bool     // не различать регистр и другие различия третьего уровня
+	IgnoreAccents bool     // не различать диакритику (второй уровень)
+}
+
+// caseLevel — третий уровень без элементов диакритики, у которых нет первичного веса:
+// при IgnoreAccents диакритика не должна влиять и на третий уровень
+const caseLevel = 3
+
+func (c CollateC) Compare(a, b string) int {
+	if a == b {
+		return 0
+	}
+
+	d := loadDUCET()
+	ba, bb := collationPool.Get().(*collationBuffers), collationPool.Get().(*collationBuffers)
+	defer collationPool.Put(ba)
+	defer collationPool.Put(bb)
+	ea, eb := ba.elements(d, a), bb.elements(d, b)
+
+	// Следующий уровень сравнивается, только если предыдущие равны
+	levels, n := c.levels()
+	for _, level := range levels[:n] {
+		if r := compareLevel(ea, eb, level); r != 0 {
+			return r
+		}
+	}
+	return 0
+}
+
+// collationBuffers — буферы для разложения строки, переиспользуемые вызовами Compare
+type collationBuffers struct {
+	runes []rune
+	ces   []collationElement
+}
+
+var collationPool = sync.Pool{New: func() any { return new(collationBuffers) }}
+
+// elements возвращает элементы сравнения s в буфере b, действительные до следующего вызова
+func (b *collationBuffers) elements(d *ducet, s string) []collationElement {
+	b.runes = d.appendNFD(b.runes[:0], s)
+	b.ces = d.appendElements(b.ces[:0], b.runes)
+	return b.ces
+}
+
+// Key возвращает ключ сортировки s: для любых строк a и b
+// bytes.Compare(c.Key(a), c.Key(b)) = c.Compare(a, b)
+func (c CollateC) Key(s string) []byte {
+	ces := loadDUCET().elements(s)
+	key := make([]byte, 0, 6*len(ces))
+	levels, n := c.levels()
+	for i, level := range levels[:n] {
+		if i > 0 {
+			key = append(key, 0, 0)
+		}
+		for _, ce := range ces {
+			if w := weight(ce, level); w != 0 {
+				key = append(key, byte(w>>8), byte(w))
+			}
+		}
+	}
+	return key
+}
+
+// levels возвращает номера сравниваемых уровней, начиная с нуля, в первых n элементах levels
+func (c CollateC) levels() (levels [3]int, n int) {
+	strength := c.Strength
+	if strength == 0 {
+		strength = Tertiary
+	}
+
+	n = 1 // первичный уровень сравнивается всегда
+	if strength >= Secondary && !c.IgnoreAccents {
+		levels[n] = 1
+		n++
+	}
+	switch {
+	case strength < Tertiary || c.IgnoreCase:
+	case c.IgnoreAccents:
+		levels[n] = caseLevel
+		n++
+	default:
+		levels[n] = 2
+		n++
+	}
+	return levels, n
+}
+
+func weight(ce collationElement, level int) uint16 {
+	if level == caseLevel {
+		if ce[0] == 0 {
+			return 0
+		}
+		level = 2
+	}
+	return ce[level]
+}
+
+// compareLevel лексикографически сравнивает ненулевые веса уровня level
+func compareLevel(a, b []collationElement, level int) int {
+	i, j := 0, 0
+	for {
+		for i < len(a) && weight(a[i], level) == 0 {
+			i++
+		}
+		for j < len(b) && weight(b[j], level) == 0 {
+			j++
+		}
+		switch {
+		case i == len(a) && j == len(b):
+			return 0
+		case i == len(a):
+			return -1
+		case j == len(b):
+			return 1
+		}
+		if r := cmp.Compare(weight(a[i], level), weight(b[j], level)); r != 0 {
+			return r
+		}
+		i++
+		j++
+	}
+}
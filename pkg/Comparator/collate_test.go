@@ -0,0 +1,239 @@
+package comparator
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"io"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+func TestCollateC(t *testing.T) {
+	var (
+		tertiary    = CollateC{}
+		secondary   = CollateC{Strength: Secondary}
+		primary     = CollateC{Strength: Primary}
+		noCase      = CollateC{IgnoreCase: true}
+		noAccents   = CollateC{IgnoreAccents: true}
+		baseLetters = CollateC{IgnoreCase: true, IgnoreAccents: true}
+	)
+
+	tests := []struct {
+		name     string
+		comp     CollateC
+		a, b     string
+		expected int
+	}{
+		{"umlaut before next letter", tertiary, "Ärger", "Zebra", -1},
+		{"case after letters", tertiary, "apple", "Banana", -1},
+		{"lower before upper", tertiary, "a", "A", -1},
+		{"accent before case", tertiary, "résumé", "Resume", 1},
+		{"canonical equivalence", tertiary, "\u00E9", "e\u0301", 0},
+		{"reordered marks", tertiary, "a\u0302\u0323", "a\u0323\u0302", 0},
+		{"hangul syllable equals jamo", tertiary, "\uD55C", "\u1112\u1161\u11AB", 0},
+		{"cyrillic after latin", tertiary, "zoo", "ёж", -1},
+		{"cyrillic short i", tertiary, "и", "й", -1},
+		{"digits before letters", tertiary, "9", "a", -1},
+		{"space is not ignored", tertiary, "a b", "ab", -1},
+		{"empty string", tertiary, "", "a", -1},
+		{"han implicit order", tertiary, "一", "丁", -1},
+		{"secondary ignores case", secondary, "Résumé", "résumé", 0},
+		{"secondary keeps accents", secondary, "resume", "résumé", -1},
+		{"primary ignores accents and case", primary, "Résumé", "resume", 0},
+		{"primary keeps letters", primary, "resume", "resumf", -1},
+		{"ignore case", noCase, "STRASSE", "strasse", 0},
+		{"ignore case keeps accents", noCase, "Cote", "côte", -1},
+		{"ignore accents", noAccents, "côté", "cote", 0},
+		{"ignore accents keeps case", noAccents, "Côte", "cote", 1},
+		{"ignore both", baseLetters, "CÔTÉ", "cote", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.comp.Compare(tt.a, tt.b); result != tt.expected {
+				t.Errorf("%+v.Compare(%q, %q) = %d, want %d", tt.comp, tt.a, tt.b, result, tt.expected)
+			}
+			if result := tt.comp.Compare(tt.b, tt.a); result != -tt.expected {
+				t.Errorf("%+v.Compare(%q, %q) = %d, want %d", tt.comp, tt.b, tt.a, result, -tt.expected)
+			}
+			if result := bytes.Compare(tt.comp.Key(tt.a), tt.comp.Key(tt.b)); result != tt.expected {
+				t.Errorf("Key comparison for %q and %q = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCollateCSort(t *testing.T) {
+	data := []string{"Zebra", "ärger", "apple", "Ärger", "Apple", "zoo", "Äpfel", "arg"}
+	expected := []string{"Äpfel", "apple", "Apple", "arg", "ärger", "Ärger", "Zebra", "zoo"}
+
+	slices.SortFunc(data, CollateC{}.Compare)
+
+	if !slices.Equal(data, expected) {
+		t.Errorf("Sorted = %q, want %q", data, expected)
+	}
+	if err := Validate[string](CollateC{}, data); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestCollateCCompareAllocs(t *testing.T) {
+	// Буферы разложения берутся из пула, так что в сортировке Compare не выделяет память
+	if raceEnabled {
+		t.Skip("sync.Pool drops objects under the race detector")
+	}
+	words := []string{"Ärger", "Zebra", "résumé", "resume", "한국어", "漢字", "\u0438\u0323\u0306"}
+	for _, c := range collationComparators {
+		c.Compare(words[0], words[1])
+		allocs := testing.AllocsPerRun(100, func() {
+			for i := 1; i < len(words); i++ {
+				c.Compare(words[i-1], words[i])
+			}
+		})
+		if allocs != 0 {
+			t.Errorf("%+v.Compare allocated %v times per run, want 0", c, allocs)
+		}
+	}
+}
+
+// collationLine — строка тестовых данных: строка и, если известны, результаты
+// сравнения предыдущей строки с этой на каждом из уровней, включая caseLevel
+type collationLine struct {
+	s         string
+	relations []int
+}
+
+// readCollationTest читает файл в формате CollationTest. Строки с суррогатами,
+// которые нельзя представить в string, пропускаются
+func readCollationTest(t *testing.T, r io.Reader) []collationLine {
+	var lines []collationLine
+	sc := bufio.NewScanner(r)
+	for sc.Scan() {
+		text, _, _ := strings.Cut(sc.Text(), "#")
+		codes, rest, _ := strings.Cut(text, ";")
+		if strings.TrimSpace(codes) == "" {
+			continue
+		}
+
+		var sb strings.Builder
+		valid := true
+		for _, f := range strings.Fields(codes) {
+			v, err := strconv.ParseUint(f, 16, 32)
+			if err != nil {
+				t.Fatalf("Malformed line %q: %v", sc.Text(), err)
+			}
+			if v >= 0xD800 && v <= 0xDFFF {
+				valid = false
+			}
+			sb.WriteRune(rune(v))
+		}
+
+		line := collationLine{s: sb.String()}
+		for _, f := range strings.Fields(rest) {
+			line.relations = append(line.relations, strings.Index("<=>", f)-1)
+		}
+		if valid {
+			lines = append(lines, line)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		t.Fatal(err)
+	}
+	return lines
+}
+
+// openCollationTest читает сжатый gzip файл testdata/name в формате CollationTest
+func openCollationTest(t *testing.T, name string) []collationLine {
+	f, err := os.Open(filepath.Join("testdata", name))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return readCollationTest(t, zr)
+}
+
+// collationComparators — параметры CollateC, с которыми проверяются тестовые данные
+var collationComparators = []CollateC{
+	{},
+	{Strength: Secondary},
+	{Strength: Primary},
+	{IgnoreCase: true},
+	{IgnoreAccents: true},
+	{IgnoreCase: true, IgnoreAccents: true},
+}
+
+// checkCollationOrder проверяет, что lines упорядочены по CollateC. Для строк
+// без результатов сравнения по уровням проверяется только нестрогий порядок
+func checkCollationOrder(t *testing.T, lines []collationLine) {
+	t.Helper()
+	failures := 0
+	for i := 1; i < len(lines) && failures < 20; i++ {
+		prev, cur := lines[i-1], lines[i]
+
+		if cur.relations == nil {
+			if r := (CollateC{}).Compare(prev.s, cur.s); r > 0 {
+				t.Errorf("Compare(%+q, %+q) = %d, want <= 0", prev.s, cur.s, r)
+				failures++
+			}
+			continue
+		}
+
+		for _, c := range collationComparators {
+			expected := 0
+			levels, n := c.levels()
+			for _, level := range levels[:n] {
+				if expected = cur.relations[level]; expected != 0 {
+					break
+				}
+			}
+			if r := c.Compare(prev.s, cur.s); r != expected {
+				t.Errorf("%+v.Compare(%+q, %+q) = %d, want %d", c, prev.s, cur.s, r, expected)
+				failures++
+			}
+		}
+	}
+}
+
+// TestCollationConformance проверяет CollateC по официальному файлу
+// CollationTest_NON_IGNORABLE_SHORT.txt из CollationTest.zip UCA 13.0.0
+// (см. testdata/README.md)
+func TestCollationConformance(t *testing.T) {
+	const name = "CollationTest_NON_IGNORABLE_SHORT.txt.gz"
+	lines := openCollationTest(t, name)
+	if len(lines) < 100000 {
+		t.Fatalf("read %d lines from testdata/%s, want the whole file", len(lines), name)
+	}
+	checkCollationOrder(t, lines)
+}
+
+// TestCollationUnicodeCollate сверяет CollateC с Unicode::Collate: в отличие от
+// официального файла, collation_test.txt.gz содержит результаты сравнения
+// по каждому уровню и проверяет Strength, IgnoreCase и IgnoreAccents
+func TestCollationUnicodeCollate(t *testing.T) {
+	checkCollationOrder(t, openCollationTest(t, "collation_test.txt.gz"))
+}
+
+func BenchmarkCollateC(b *testing.B) {
+	words := []string{"Ärger", "Zebra", "résumé", "resume", "ёжик", "Straße", "naïve", "coöperate"}
+	comp := CollateC{}
+
+	b.Run("Compare", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			comp.Compare(words[i%len(words)], words[(i+1)%len(words)])
+		}
+	})
+	b.Run("Key", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			comp.Key(words[i%len(words)])
+		}
+	})
+}
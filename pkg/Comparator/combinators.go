@@ -0,0 +1,82 @@
+package comparator
+
+// Reverse возвращает компаратор с обратным порядком
+func Reverse[T any](c Comparator[T]) Comparator[T] {
+	return reverseC[T]{c}
+}
+
+type reverseC[T any] struct {
+	c Comparator[T]
+}
+
+func (r reverseC[T]) Compare(a, b T) int {
+	return r.c.Compare(b, a)
+}
+
+// Chain возвращает лексикографическую комбинацию компараторов:
+// элементы сравниваются первым из cs, при равенстве — вторым и так далее.
+// Без аргументов все элементы равны
+func Chain[T any](cs ...Comparator[T]) Comparator[T] {
+	return chainC[T](cs)
+}
+
+type chainC[T any] []Comparator[T]
+
+func (ch chainC[T]) Compare(a, b T) int {
+	for _, c := range ch {
+		if r := c.Compare(a, b); r != 0 {
+			return r
+		}
+	}
+	return 0
+}
+
+// By возвращает компаратор, сравнивающий элементы по ключу key с помощью kc.
+// key вызывается при каждом сравнении, поэтому должен быть дешёвым
+func By[T, K any](key func(T) K, kc Comparator[K]) Comparator[T] {
+	return byC[T, K]{key, kc}
+}
+
+type byC[T, K any] struct {
+	key func(T) K
+	kc  Comparator[K]
+}
+
+func (b byC[T, K]) Compare(x, y T) int {
+	return b.kc.Compare(b.key(x), b.key(y))
+}
+
+// ThenBy возвращает компаратор, который при равенстве по c сравнивает
+// элементы по ключу key, то есть Chain(c, By(key, kc))
+func ThenBy[T, K any](c Comparator[T], key func(T) K, kc Comparator[K]) Comparator[T] {
+	return Chain(c, By(key, kc))
+}
+
+// NullsFirst возвращает компаратор указателей: nil меньше любого другого
+// указателя, а значения по ненулевым указателям сравниваются c
+func NullsFirst[T any](c Comparator[T]) Comparator[*T] {
+	return nullsC[T]{c, -1}
+}
+
+// NullsLast возвращает компаратор указателей: nil больше любого другого
+// указателя, а значения по ненулевым указателям сравниваются c
+func NullsLast[T any](c Comparator[T]) Comparator[*T] {
+	return nullsC[T]{c, 1}
+}
+
+type nullsC[T any] struct {
+	c        Comparator[T]
+	nilOrder int // результат сравнения nil с ненулевым указателем
+}
+
+func (n nullsC[T]) Compare(a, b *T) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return n.nilOrder
+	case b == nil:
+		return -n.nilOrder
+	}
+	return n.c.Compare(*a, *b)
+}
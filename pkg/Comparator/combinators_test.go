@@ -0,0 +1,148 @@
+package comparator
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type employee struct {
+	name string
+	dept string
+	age  int
+}
+
+func employeeName(e employee) string { return e.name }
+func employeeDept(e employee) string { return e.dept }
+func employeeAge(e employee) int     { return e.age }
+
+func TestReverse(t *testing.T) {
+	comp := Reverse[int](IntC{})
+
+	tests := []struct {
+		name     string
+		a, b     int
+		expected int
+	}{
+		{"a less than b", 1, 2, 1},
+		{"a greater than b", 2, 1, -1},
+		{"a equals b", 3, 3, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := comp.Compare(tt.a, tt.b); result != tt.expected {
+				t.Errorf("Reverse(IntC).Compare(%d, %d) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+
+	t.Run("double reverse", func(t *testing.T) {
+		if result := Reverse(comp).Compare(1, 2); result != -1 {
+			t.Errorf("Reverse(Reverse(IntC)).Compare(1, 2) = %d, want -1", result)
+		}
+	})
+}
+
+func TestChain(t *testing.T) {
+	byDept := By(employeeDept, StringC{})
+	byAge := By(employeeAge, IntC{})
+
+	tests := []struct {
+		name     string
+		comp     Comparator[employee]
+		a, b     employee
+		expected int
+	}{
+		{"first comparator decides", Chain(byDept, byAge), employee{"", "dev", 50}, employee{"", "ops", 20}, -1},
+		{"tie broken by second", Chain(byDept, byAge), employee{"", "dev", 50}, employee{"", "dev", 20}, 1},
+		{"all equal", Chain(byDept, byAge), employee{"a", "dev", 20}, employee{"b", "dev", 20}, 0},
+		{"reversed tie breaker", Chain(byDept, Reverse(byAge)), employee{"", "dev", 50}, employee{"", "dev", 20}, -1},
+		{"ThenBy", ThenBy(byDept, employeeName, StringC{}), employee{"bob", "dev", 0}, employee{"alice", "dev", 0}, 1},
+		{"empty chain", Chain[employee](), employee{"a", "dev", 1}, employee{"b", "ops", 2}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.comp.Compare(tt.a, tt.b); result != tt.expected {
+				t.Errorf("Compare(%v, %v) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBy(t *testing.T) {
+	comp := By(func(s string) int { return len(s) }, IntC{})
+
+	tests := []struct {
+		name     string
+		a, b     string
+		expected int
+	}{
+		{"shorter first", "go", "rust", -1},
+		{"longer last", "haskell", "c", 1},
+		{"same key", "abc", "xyz", 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := comp.Compare(tt.a, tt.b); result != tt.expected {
+				t.Errorf("By(len).Compare(%q, %q) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNulls(t *testing.T) {
+	one, two := 1, 2
+
+	tests := []struct {
+		name     string
+		comp     Comparator[*int]
+		a, b     *int
+		expected int
+	}{
+		{"NullsFirst nil vs value", NullsFirst[int](IntC{}), nil, &one, -1},
+		{"NullsFirst value vs nil", NullsFirst[int](IntC{}), &one, nil, 1},
+		{"NullsFirst both nil", NullsFirst[int](IntC{}), nil, nil, 0},
+		{"NullsFirst values", NullsFirst[int](IntC{}), &two, &one, 1},
+		{"NullsLast nil vs value", NullsLast[int](IntC{}), nil, &one, 1},
+		{"NullsLast value vs nil", NullsLast[int](IntC{}), &one, nil, -1},
+		{"NullsLast both nil", NullsLast[int](IntC{}), nil, nil, 0},
+		{"NullsLast values", NullsLast[int](IntC{}), &one, &two, -1},
+		{"Reverse keeps nulls last", Reverse(NullsFirst(Reverse[int](IntC{}))), nil, &one, 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.comp.Compare(tt.a, tt.b); result != tt.expected {
+				t.Errorf("Compare() = %d, want %d", result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestCombinatorsSort(t *testing.T) {
+	data := []employee{
+		{"carol", "ops", 35},
+		{"alice", "dev", 30},
+		{"dave", "dev", 41},
+		{"bob", "ops", 35},
+		{"erin", "dev", 30},
+	}
+	expected := []employee{
+		{"dave", "dev", 41},
+		{"alice", "dev", 30},
+		{"erin", "dev", 30},
+		{"bob", "ops", 35},
+		{"carol", "ops", 35},
+	}
+
+	// Отдел по возрастанию, возраст по убыванию, затем имя
+	comp := Chain(By(employeeDept, StringC{}), Reverse(By(employeeAge, IntC{})), By(employeeName, StringC{}))
+	slices.SortFunc(data, comp.Compare)
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("Sorted = %v, want %v", data, expected)
+	}
+}
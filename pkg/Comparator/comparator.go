@@ -1,8 +1,15 @@
 package comparator
 
+import (
+	"bytes"
+	"cmp"
+	"math"
+	"reflect"
+)
+
 // Компаратор - интерфейс для сравнения элементов
 type Comparator[T any] interface {
-	Compare(a, b T) int // -1 если a < b, 0 если a == b, 1 если a > b
+	Compare(a, b T) int // < 0 если a < b, 0 если a == b, > 0 если a > b
 }
 
 // Компаратор для целых чисел
@@ -30,3 +37,72 @@ func (c StringC) Compare(a, b string) int {
 	}
 	return 0
 }
+
+// Компаратор для срезов байт в лексикографическом порядке, как bytes.Compare,
+// например для ключей CollateC.Key
+type BytesC struct{}
+
+func (c BytesC) Compare(a, b []byte) int {
+	return bytes.Compare(a, b)
+}
+
+// Компаратор для любого упорядоченного типа: целых чисел всех размеров,
+// чисел с плавающей точкой и строк. Порядок полный: NaN меньше всех
+// остальных чисел и равен любому другому NaN, а -0 меньше +0
+type OrderedC[T cmp.Ordered] struct{}
+
+func (c OrderedC[T]) Compare(a, b T) int {
+	if r := cmp.Compare(a, b); r != 0 {
+		return r
+	}
+
+	// cmp.Compare считает -0 и +0 равными, различаем их по знаку
+	var zero T
+	if a != zero {
+		return 0
+	}
+	return cmp.Compare(signbit(b), signbit(a))
+}
+
+// signbit возвращает 1 для отрицательного нуля и 0 для остальных значений.
+// Встроенные типы различаются переключателем типов, и только для именованных
+// типов вроде type celsius float64 нужен reflect
+func signbit[T cmp.Ordered](x T) int {
+	switch f := any(x).(type) {
+	case float64:
+		if math.Signbit(f) {
+			return 1
+		}
+		return 0
+	case float32:
+		if math.Signbit(float64(f)) {
+			return 1
+		}
+		return 0
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, uintptr, string:
+		return 0
+	}
+
+	v := reflect.ValueOf(x)
+	if k := v.Kind(); (k == reflect.Float32 || k == reflect.Float64) && math.Signbit(v.Float()) {
+		return 1
+	}
+	return 0
+}
+
+// ComparatorFunc позволяет использовать функцию сравнения в стиле
+// cmp.Compare и slices.SortFunc как компаратор: ComparatorFunc[int](cmp.Compare[int])
+type ComparatorFunc[T any] func(a, b T) int
+
+func (f ComparatorFunc[T]) Compare(a, b T) int {
+	return f(a, b)
+}
+
+// Func возвращает функцию сравнения c для slices.SortFunc, slices.BinarySearchFunc
+// и подобных. Для ComparatorFunc возвращается исходная функция
+func Func[T any](c Comparator[T]) func(a, b T) int {
+	if f, ok := c.(ComparatorFunc[T]); ok {
+		return f
+	}
+	return c.Compare
+}
@@ -1,6 +1,11 @@
 package comparator
 
 import (
+	"cmp"
+	"fmt"
+	"math"
+	"slices"
+	"strings"
 	"testing"
 )
 
@@ -218,6 +223,156 @@ func TestStringComparator(t *testing.T) {
 	}
 }
 
+// testOrdered проверяет OrderedC на всех парах из ascending — значений по возрастанию
+func testOrdered[T cmp.Ordered](t *testing.T, ascending []T) {
+	comp := OrderedC[T]{}
+
+	t.Run(fmt.Sprintf("%T", ascending[0]), func(t *testing.T) {
+		for i, a := range ascending {
+			for j, b := range ascending {
+				expected := cmp.Compare(i, j)
+				if result := comp.Compare(a, b); result != expected {
+					t.Errorf("OrderedC.Compare(%v, %v) = %d, want %d", a, b, result, expected)
+				}
+			}
+		}
+	})
+}
+
+func TestBytesComparator(t *testing.T) {
+	comp := BytesC{}
+
+	tests := []struct {
+		name     string
+		a, b     []byte
+		expected int
+	}{
+		{"less", []byte("apple"), []byte("banana"), -1},
+		{"greater", []byte{0xff}, []byte{0x00, 0xff}, 1},
+		{"prefix", []byte("ab"), []byte("abc"), -1},
+		{"equal", []byte("key"), []byte("key"), 0},
+		{"nil and empty", nil, []byte{}, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := comp.Compare(tt.a, tt.b); result != tt.expected {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+			if result := comp.Compare(tt.b, tt.a); result != -tt.expected {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.b, tt.a, result, -tt.expected)
+			}
+		})
+	}
+}
+
+type celsius float64
+
+type rank int
+
+func TestOrderedComparator(t *testing.T) {
+	testOrdered(t, []int{math.MinInt, -1, 0, 1, math.MaxInt})
+	testOrdered(t, []int8{math.MinInt8, -1, 0, 1, math.MaxInt8})
+	testOrdered(t, []int16{math.MinInt16, -1, 0, 1, math.MaxInt16})
+	testOrdered(t, []int32{math.MinInt32, -1, 0, 1, math.MaxInt32})
+	testOrdered(t, []int64{math.MinInt64, -1, 0, 1, math.MaxInt64})
+	testOrdered(t, []uint{0, 1, math.MaxUint})
+	testOrdered(t, []uint8{0, 1, math.MaxUint8})
+	testOrdered(t, []uint16{0, 1, math.MaxUint16})
+	testOrdered(t, []uint32{0, 1, math.MaxUint32})
+	testOrdered(t, []uint64{0, 1, math.MaxUint64})
+	testOrdered(t, []uintptr{0, 1, 1 << 20})
+	testOrdered(t, []float32{
+		float32(math.Inf(-1)), -math.MaxFloat32, -1, -math.SmallestNonzeroFloat32,
+		float32(math.Copysign(0, -1)), 0, math.SmallestNonzeroFloat32, 1, math.MaxFloat32, float32(math.Inf(1)),
+	})
+	testOrdered(t, []float64{
+		math.Inf(-1), -math.MaxFloat64, -1, -math.SmallestNonzeroFloat64,
+		math.Copysign(0, -1), 0, math.SmallestNonzeroFloat64, 1, math.MaxFloat64, math.Inf(1),
+	})
+	testOrdered(t, []celsius{celsius(math.Copysign(0, -1)), 0, 36.6})
+	testOrdered(t, []rank{-1, 0, 1})
+	testOrdered(t, []string{"", "A", "Z", "a", "café", "cat", "catch"})
+}
+
+func TestOrderedComparatorNaN(t *testing.T) {
+	comp := OrderedC[float64]{}
+	nan, otherNaN := math.NaN(), math.Float64frombits(0x7ff8000000000001)
+
+	tests := []struct {
+		name     string
+		a, b     float64
+		expected int
+	}{
+		{"NaN equals NaN", nan, nan, 0},
+		{"NaNs with different payloads are equal", nan, otherNaN, 0},
+		{"NaN less than negative infinity", nan, math.Inf(-1), -1},
+		{"NaN less than negative zero", nan, math.Copysign(0, -1), -1},
+		{"number greater than NaN", 42, nan, 1},
+		{"negative zero less than positive zero", math.Copysign(0, -1), 0, -1},
+		{"positive zero greater than negative zero", 0, math.Copysign(0, -1), 1},
+		{"negative zeros equal", math.Copysign(0, -1), math.Copysign(0, -1), 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := comp.Compare(tt.a, tt.b)
+			if result != tt.expected {
+				t.Errorf("OrderedC.Compare(%v, %v) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestComparatorFunc(t *testing.T) {
+	byLength := func(a, b string) int { return cmp.Compare(len(a), len(b)) }
+
+	tests := []struct {
+		name     string
+		comp     Comparator[string]
+		a, b     string
+		expected int
+	}{
+		{"cmp.Compare", ComparatorFunc[string](cmp.Compare[string]), "apple", "banana", -1},
+		{"strings.Compare", ComparatorFunc[string](strings.Compare), "b", "a", 1},
+		{"closure", ComparatorFunc[string](byLength), "abc", "xyz", 0},
+		{"combined with Reverse", Reverse[string](ComparatorFunc[string](byLength)), "go", "rust", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.comp.Compare(tt.a, tt.b); result != tt.expected {
+				t.Errorf("Compare(%q, %q) = %d, want %d", tt.a, tt.b, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestFunc(t *testing.T) {
+	data := []string{"pear", "fig", "banana", "kiwi"}
+
+	t.Run("from struct comparator", func(t *testing.T) {
+		sorted := slices.Clone(data)
+		slices.SortFunc(sorted, Func[string](StringC{}))
+		if expected := []string{"banana", "fig", "kiwi", "pear"}; !slices.Equal(sorted, expected) {
+			t.Errorf("slices.SortFunc(Func(StringC)) = %v, want %v", sorted, expected)
+		}
+	})
+
+	t.Run("round trip", func(t *testing.T) {
+		var calls int
+		f := func(a, b string) int {
+			calls++
+			return cmp.Compare(len(a), len(b))
+		}
+
+		back := Func[string](ComparatorFunc[string](f))
+		if result := back("fig", "pear"); result != -1 || calls != 1 {
+			t.Errorf("Func(ComparatorFunc(f))(fig, pear) = %d after %d calls, want -1 after 1", result, calls)
+		}
+	})
+}
+
 // Тест для проверки, что компараторы реализуют интерфейс Comparator
 func TestComparatorInterface(t *testing.T) {
 	t.Run("IntC implements Comparator", func(t *testing.T) {
@@ -227,6 +382,14 @@ func TestComparatorInterface(t *testing.T) {
 	t.Run("StringC implements Comparator", func(t *testing.T) {
 		var _ Comparator[string] = StringC{}
 	})
+
+	t.Run("OrderedC implements Comparator", func(t *testing.T) {
+		var _ Comparator[float64] = OrderedC[float64]{}
+	})
+
+	t.Run("ComparatorFunc implements Comparator", func(t *testing.T) {
+		var _ Comparator[int] = ComparatorFunc[int](cmp.Compare[int])
+	})
 }
 
 // Benchmark тесты
@@ -237,6 +400,29 @@ func BenchmarkIntComparator(b *testing.B) {
 	}
 }
 
+func BenchmarkOrderedComparator(b *testing.B) {
+	comp := OrderedC[int]{}
+	for i := 0; i < b.N; i++ {
+		comp.Compare(i, i+1)
+	}
+}
+
+// Равные нули: OrderedC дополнительно сравнивает знаки
+func BenchmarkOrderedComparatorZeros(b *testing.B) {
+	b.Run("int", func(b *testing.B) { benchmarkZeros[int](b) })
+	b.Run("float64", func(b *testing.B) { benchmarkZeros[float64](b) })
+	b.Run("string", func(b *testing.B) { benchmarkZeros[string](b) })
+	b.Run("celsius", func(b *testing.B) { benchmarkZeros[celsius](b) })
+}
+
+func benchmarkZeros[T cmp.Ordered](b *testing.B) {
+	comp := OrderedC[T]{}
+	var zero T
+	for i := 0; i < b.N; i++ {
+		comp.Compare(zero, zero)
+	}
+}
+
 func BenchmarkStringComparator(b *testing.B) {
 	comp := StringC{}
 	s1 := "benchmark"
@@ -0,0 +1,350 @@
+package comparator
+
+import (
+	"bufio"
+	"compress/gzip"
+	"embed"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Таблица DUCET 13.0.0 и данные для NFD (Unicode 13.0), см. ucadata/README.md
+//
+//go:embed ucadata/allkeys.txt.gz ucadata/nfd.txt.gz
+var ucadata embed.FS
+
+// collationElement — веса элемента сравнения на первых трёх уровнях
+type collationElement [3]uint16
+
+// implicitRange — диапазон символов с неявными весами из строки @implicitweights
+type implicitRange struct {
+	lo, hi rune
+	base   uint16
+	offset rune // от этого символа отсчитывается второй вес
+}
+
+type ducet struct {
+	single   map[rune][]collationElement
+	multi    map[string][]collationElement // сокращения из нескольких символов
+	prefixes map[string]bool               // собственные префиксы сокращений
+	implicit []implicitRange
+
+	decomposition map[rune][]rune // полные канонические декомпозиции
+	combining     map[rune]uint8  // ненулевые классы канонического комбинирования
+}
+
+// loadDUCET разбирает встроенные таблицы при первом обращении
+var loadDUCET = sync.OnceValue(func() *ducet {
+	d := &ducet{
+		single:        make(map[rune][]collationElement),
+		multi:         make(map[string][]collationElement),
+		prefixes:      make(map[string]bool),
+		decomposition: make(map[rune][]rune),
+		combining:     make(map[rune]uint8),
+	}
+	if err := readLines("ucadata/allkeys.txt.gz", d.parseAllKeys); err != nil {
+		panic(err)
+	}
+	if err := readLines("ucadata/nfd.txt.gz", d.parseNFD); err != nil {
+		panic(err)
+	}
+	return d
+})
+
+func readLines(name string, parse func(line string) error) error {
+	f, err := ucadata.Open(name)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	zr, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("comparator: %s: %w", name, err)
+	}
+	sc := bufio.NewScanner(zr)
+	for n := 1; sc.Scan(); n++ {
+		line, _, _ := strings.Cut(sc.Text(), "#")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		if err := parse(line); err != nil {
+			return fmt.Errorf("comparator: %s:%d: %w", name, n, err)
+		}
+	}
+	return sc.Err()
+}
+
+// parseAllKeys разбирает строку allkeys.txt:
+//
+//	0041 0301 ; [.1FA2.0020.0008][.0000.0024.0002]
+//	@implicitweights 17000..18AFF; FB00
+func (d *ducet) parseAllKeys(line string) error {
+	if rest, ok := strings.CutPrefix(line, "@implicitweights"); ok {
+		rng, base, _ := strings.Cut(rest, ";")
+		lo, hi, _ := strings.Cut(strings.TrimSpace(rng), "..")
+		r := implicitRange{}
+		var err error
+		if r.lo, err = parseRune(lo); err != nil {
+			return err
+		}
+		if r.hi, err = parseRune(hi); err != nil {
+			return err
+		}
+		b, err := strconv.ParseUint(strings.TrimSpace(base), 16, 16)
+		if err != nil {
+			return err
+		}
+		r.base, r.offset = uint16(b), r.lo
+		// Диапазоны с общим весом продолжают нумерацию первого из них
+		for _, prev := range d.implicit {
+			if prev.base == r.base {
+				r.offset = prev.offset
+				break
+			}
+		}
+		d.implicit = append(d.implicit, r)
+		return nil
+	}
+	if strings.HasPrefix(line, "@") {
+		return nil
+	}
+
+	chars, weights, ok := strings.Cut(line, ";")
+	if !ok {
+		return fmt.Errorf("missing ';' in %q", line)
+	}
+	runes, err := parseRunes(chars)
+	if err != nil {
+		return err
+	}
+
+	var ces []collationElement
+	for _, w := range strings.Split(weights, "[")[1:] {
+		w, _, _ = strings.Cut(w, "]")
+		fields := strings.Split(w[1:], ".") // первый символ — '.' или '*' у переменных элементов
+		if len(fields) != 3 {
+			return fmt.Errorf("malformed collation element [%s]", w)
+		}
+		var ce collationElement
+		for i, f := range fields {
+			v, err := strconv.ParseUint(f, 16, 16)
+			if err != nil {
+				return err
+			}
+			ce[i] = uint16(v)
+		}
+		ces = append(ces, ce)
+	}
+
+	if len(runes) == 1 {
+		d.single[runes[0]] = ces
+		return nil
+	}
+	d.multi[string(runes)] = ces
+	for i := 1; i < len(runes); i++ {
+		d.prefixes[string(runes[:i])] = true
+	}
+	return nil
+}
+
+// parseNFD разбирает строку nfd.txt: "00C0;0;0041 0300"
+func (d *ducet) parseNFD(line string) error {
+	fields := strings.Split(line, ";")
+	if len(fields) != 3 {
+		return fmt.Errorf("malformed line %q", line)
+	}
+	r, err := parseRune(fields[0])
+	if err != nil {
+		return err
+	}
+	ccc, err := strconv.ParseUint(fields[1], 10, 8)
+	if err != nil {
+		return err
+	}
+	if ccc != 0 {
+		d.combining[r] = uint8(ccc)
+	}
+	if fields[2] != "" {
+		if d.decomposition[r], err = parseRunes(fields[2]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseRune(s string) (rune, error) {
+	v, err := strconv.ParseUint(strings.TrimSpace(s), 16, 32)
+	return rune(v), err
+}
+
+func parseRunes(s string) ([]rune, error) {
+	var runes []rune
+	for _, f := range strings.Fields(s) {
+		r, err := parseRune(f)
+		if err != nil {
+			return nil, err
+		}
+		runes = append(runes, r)
+	}
+	return runes, nil
+}
+
+// Алгоритмическая декомпозиция слогов хангыля
+const (
+	hangulSBase  = 0xAC00
+	hangulLBase  = 0x1100
+	hangulVBase  = 0x1161
+	hangulTBase  = 0x11A7
+	hangulVCount = 21
+	hangulTCount = 28
+	hangulNCount = hangulVCount * hangulTCount
+	hangulSCount = 19 * hangulNCount
+)
+
+// nfd возвращает каноническую декомпозицию s с каноническим упорядочением
+func (d *ducet) nfd(s string) []rune {
+	return d.appendNFD(make([]rune, 0, len(s)), s)
+}
+
+// appendNFD дописывает к runes каноническую декомпозицию s с каноническим упорядочением
+func (d *ducet) appendNFD(runes []rune, s string) []rune {
+	start := len(runes)
+	for _, r := range s {
+		switch {
+		case r >= hangulSBase && r < hangulSBase+hangulSCount:
+			i := r - hangulSBase
+			runes = append(runes, hangulLBase+i/hangulNCount, hangulVBase+i%hangulNCount/hangulTCount)
+			if t := i % hangulTCount; t != 0 {
+				runes = append(runes, hangulTBase+t)
+			}
+		case d.decomposition[r] != nil:
+			runes = append(runes, d.decomposition[r]...)
+		default:
+			runes = append(runes, r)
+		}
+	}
+
+	// Устойчивая сортировка вставками по классу комбинирования внутри
+	// каждой последовательности символов с ненулевым классом
+	for i := start + 1; i < len(runes); i++ {
+		c := d.combining[runes[i]]
+		if c == 0 {
+			continue
+		}
+		for j := i; j > start && d.combining[runes[j-1]] > c; j-- {
+			runes[j], runes[j-1] = runes[j-1], runes[j]
+		}
+	}
+	return runes
+}
+
+// elements возвращает элементы сравнения s (шаг S2 алгоритма UCA)
+func (d *ducet) elements(s string) []collationElement {
+	runes := d.nfd(s)
+	return d.appendElements(make([]collationElement, 0, len(runes)), runes)
+}
+
+// appendElements дописывает к ces элементы сравнения строки runes, уже приведённой к NFD.
+// Символы, присоединённые к разрывным сокращениям, удаляются из runes
+func (d *ducet) appendElements(ces []collationElement, runes []rune) []collationElement {
+	for i := 0; i < len(runes); {
+		// Самое длинное совпадение среди непрерывных последовательностей
+		n, match := 0, []collationElement(nil)
+		if m, ok := d.single[runes[i]]; ok {
+			n, match = 1, m
+		}
+		for l := 2; i+l <= len(runes) && d.prefixes[string(runes[i:i+l-1])]; l++ {
+			if m, ok := d.multi[string(runes[i:i+l])]; ok {
+				n, match = l, m
+			}
+		}
+		if n == 0 {
+			implicit := d.implicitElements(runes[i])
+			ces = append(ces, implicit[:]...)
+			i++
+			continue
+		}
+
+		// Разрывные сокращения: незаблокированные символы с ненулевым классом
+		// после совпадения присоединяются к нему, если сокращение существует
+		// Совпадение хранится в UTF-8 на стеке: поиск в картах по string(matched)
+		// не выделяет память
+		var buf [32]byte
+		matched := buf[:0]
+		for _, r := range runes[i : i+n] {
+			matched = utf8.AppendRune(matched, r)
+		}
+		var skipped uint8 // наибольший класс пропущенных символов
+		for j := i + n; j < len(runes) && d.prefixes[string(matched)]; {
+			c := d.combining[runes[j]]
+			if c == 0 {
+				break
+			}
+			if c > skipped {
+				if m, ok := d.multi[string(utf8.AppendRune(matched, runes[j]))]; ok {
+					matched = utf8.AppendRune(matched, runes[j])
+					match = m
+					runes = append(runes[:j], runes[j+1:]...)
+					continue
+				}
+			}
+			skipped = max(skipped, c)
+			j++
+		}
+
+		ces = append(ces, match...)
+		i += n
+	}
+	return ces
+}
+
+// Унифицированные идеограммы Unicode 13.0
+var unifiedIdeographs = [][2]rune{
+	{0x3400, 0x4DBF}, {0x4E00, 0x9FFC}, {0xFA0E, 0xFA0F}, {0xFA11, 0xFA11}, {0xFA13, 0xFA14},
+	{0xFA1F, 0xFA1F}, {0xFA21, 0xFA21}, {0xFA23, 0xFA24}, {0xFA27, 0xFA29}, {0x20000, 0x2A6DD},
+	{0x2A700, 0x2B734}, {0x2B740, 0x2B81D}, {0x2B820, 0x2CEA1}, {0x2CEB0, 0x2EBE0}, {0x30000, 0x3134A},
+}
+
+// Назначенные в Unicode 13.0 символы блоков из строк @implicitweights:
+// неназначенные символы этих блоков получают веса по общему правилу
+var implicitAssigned = [][2]rune{
+	{0x17000, 0x187F7}, {0x18800, 0x18AFF}, {0x18B00, 0x18CD5}, {0x18D00, 0x18D08}, {0x1B170, 0x1B2FB},
+}
+
+func inRanges(r rune, ranges [][2]rune) bool {
+	for _, rng := range ranges {
+		if r >= rng[0] && r <= rng[1] {
+			return true
+		}
+	}
+	return false
+}
+
+// implicitElements вычисляет неявные веса символа, которого нет в таблице
+func (d *ducet) implicitElements(r rune) [2]collationElement {
+	if inRanges(r, implicitAssigned) {
+		for _, rng := range d.implicit {
+			if r >= rng.lo && r <= rng.hi {
+				return implicitPair(rng.base, r-rng.offset)
+			}
+		}
+	}
+
+	base := uint16(0xFBC0)
+	if inRanges(r, unifiedIdeographs) {
+		// Основной блок и совместимые идеограммы идут раньше расширений
+		base = 0xFB80
+		if r >= 0x4E00 && r <= 0xFAFF {
+			base = 0xFB40
+		}
+	}
+	return implicitPair(base+uint16(r>>15), r&0x7FFF)
+}
+
+func implicitPair(base uint16, rest rune) [2]collationElement {
+	return [2]collationElement{{base, 0x0020, 0x0002}, {uint16(rest) | 0x8000, 0, 0}}
+}
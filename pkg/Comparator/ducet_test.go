@@ -0,0 +1,95 @@
+package comparator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNFD(t *testing.T) {
+	d := loadDUCET()
+
+	tests := []struct {
+		name     string
+		s        string
+		expected []rune
+	}{
+		{"ASCII", "ab", []rune{'a', 'b'}},
+		{"precomposed", "\u00C4", []rune{0x41, 0x308}},
+		{"recursive decomposition", "\u1E09", []rune{0x63, 0x327, 0x301}},
+		{"canonical reordering", "a\u0302\u0323", []rune{0x61, 0x323, 0x302}},
+		{"equal classes keep order", "a\u0301\u0300", []rune{0x61, 0x301, 0x300}},
+		{"hangul LV", "\uAC00", []rune{0x1100, 0x1161}},
+		{"hangul LVT", "\uD55C", []rune{0x1112, 0x1161, 0x11AB}},
+		{"compatibility ideograph", "\uFA10", []rune{0x585A}},
+		{"empty", "", []rune{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := d.nfd(tt.s); !slices.Equal(result, tt.expected) {
+				t.Errorf("nfd(%q) = %U, want %U", tt.s, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestImplicitElements(t *testing.T) {
+	d := loadDUCET()
+
+	tests := []struct {
+		name     string
+		r        rune
+		expected [2]uint16 // первичные веса двух элементов
+	}{
+		{"core Han", 0x4E00, [2]uint16{0xFB40, 0xCE00}},
+		{"compatibility ideograph", 0xFA0E, [2]uint16{0xFB41, 0xFA0E}},
+		{"extension A", 0x4DBF, [2]uint16{0xFB80, 0xCDBF}},
+		{"extension B", 0x2A6DD, [2]uint16{0xFB85, 0xA6DD}},
+		{"unassigned after extension B", 0x2A6DE, [2]uint16{0xFBC5, 0xA6DE}},
+		{"Tangut", 0x17000, [2]uint16{0xFB00, 0x8000}},
+		{"Tangut supplement", 0x18D00, [2]uint16{0xFB00, 0x9D00}},
+		{"Nushu", 0x1B170, [2]uint16{0xFB01, 0x8000}},
+		{"Khitan", 0x18B00, [2]uint16{0xFB02, 0x8000}},
+		{"unassigned Khitan", 0x18CE5, [2]uint16{0xFBC3, 0x8CE5}},
+		{"unassigned", 0x0378, [2]uint16{0xFBC0, 0x8378}},
+		{"last code point", 0x10FFFF, [2]uint16{0xFBE1, 0xFFFF}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ces := d.implicitElements(tt.r)
+			expected := [2]collationElement{{tt.expected[0], 0x20, 0x02}, {tt.expected[1], 0, 0}}
+			if ces != expected {
+				t.Errorf("implicitElements(%U) = %04X, want %04X", tt.r, ces, expected)
+			}
+		})
+	}
+}
+
+func TestElementsContractions(t *testing.T) {
+	d := loadDUCET()
+	short := d.single[0x439] // й
+
+	tests := []struct {
+		name string
+		s    string
+	}{
+		{"precomposed", "\u0439"},
+		{"contiguous contraction", "\u0438\u0306"},
+		{"discontiguous contraction", "\u0438\u0323\u0306"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ces := d.elements(tt.s)
+			if len(ces) == 0 || ces[0] != short[0] {
+				t.Errorf("elements(%+q) = %04X, want to start with %04X", tt.s, ces, short)
+			}
+		})
+	}
+
+	// Блокированный символ не присоединяется к сокращению
+	if ces := d.elements("\u0438\u0306\u0306"); len(ces) != 2 || ces[0] != short[0] {
+		t.Errorf("elements(и + breve + breve) = %04X, want й and a breve", ces)
+	}
+}
@@ -0,0 +1,80 @@
+package comparator
+
+import (
+	"cmp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// NaturalStringC — компаратор строк в «естественном» порядке: последовательности
+// цифр сравниваются как числа любой длины, поэтому "img2.png" < "img10.png".
+// Остальные символы сравниваются побайтово, как в StringC. Числа, равные по значению,
+// различаются только при равенстве остального: меньше ведущих нулей — раньше,
+// "a1" < "a01" < "a001". Цифрами считаются только ASCII-цифры 0-9
+type NaturalStringC struct {
+	IgnoreCase bool // сравнивать нецифровые символы без учёта регистра
+}
+
+func (c NaturalStringC) Compare(a, b string) int {
+	zeros := 0 // результат сравнения по ведущим нулям первого различающегося числа
+	for a != "" && b != "" {
+		if isDigit(a[0]) && isDigit(b[0]) {
+			na, nb := digitPrefix(a), digitPrefix(b)
+			if r := compareNumbers(na, nb); r != 0 {
+				return r
+			}
+			if zeros == 0 {
+				zeros = cmp.Compare(len(na), len(nb))
+			}
+			a, b = a[len(na):], b[len(nb):]
+			continue
+		}
+
+		if !c.IgnoreCase {
+			if a[0] != b[0] {
+				return cmp.Compare(a[0], b[0])
+			}
+			a, b = a[1:], b[1:]
+			continue
+		}
+
+		ra, sa := utf8.DecodeRuneInString(a)
+		rb, sb := utf8.DecodeRuneInString(b)
+		if r := cmp.Compare(unicode.ToLower(ra), unicode.ToLower(rb)); r != 0 {
+			return r
+		}
+		a, b = a[sa:], b[sb:]
+	}
+
+	switch {
+	case a != "":
+		return 1
+	case b != "":
+		return -1
+	}
+	return zeros
+}
+
+func isDigit(b byte) bool {
+	return '0' <= b && b <= '9'
+}
+
+// digitPrefix возвращает последовательность цифр в начале s
+func digitPrefix(s string) string {
+	i := 0
+	for i < len(s) && isDigit(s[i]) {
+		i++
+	}
+	return s[:i]
+}
+
+// compareNumbers сравнивает записи неотрицательных чисел по значению
+func compareNumbers(a, b string) int {
+	a = strings.TrimLeft(a, "0")
+	b = strings.TrimLeft(b, "0")
+	if r := cmp.Compare(len(a), len(b)); r != 0 {
+		return r
+	}
+	return strings.Compare(a, b)
+}
@@ -0,0 +1,100 @@
+package comparator
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestNaturalStringComparator(t *testing.T) {
+	tests := []struct {
+		name     string
+		comp     NaturalStringC
+		a, b     string
+		expected int
+	}{
+		{"numbers by value", NaturalStringC{}, "img2.png", "img10.png", -1},
+		{"numbers by value reversed", NaturalStringC{}, "img10.png", "img2.png", 1},
+		{"equal strings", NaturalStringC{}, "img10.png", "img10.png", 0},
+		{"empty strings", NaturalStringC{}, "", "", 0},
+		{"empty before anything", NaturalStringC{}, "", "0", -1},
+		{"only numbers", NaturalStringC{}, "9", "10", -1},
+		{"prefix is less", NaturalStringC{}, "file", "file1", -1},
+		{"text decides before number", NaturalStringC{}, "a10", "b2", -1},
+		{"number after text", NaturalStringC{}, "v1.10", "v1.9", 1},
+		{"several numbers", NaturalStringC{}, "1.2.10", "1.2.9", 1},
+		{"first number decides", NaturalStringC{}, "1.10.1", "1.9.10", 1},
+		{"numbers longer than uint64", NaturalStringC{}, "x123456789012345678901234567890", "x99999999999999999999999999999", 1},
+		{"huge equal numbers", NaturalStringC{}, "99999999999999999999999999999999", "99999999999999999999999999999999", 0},
+		{"leading zeros tie breaker", NaturalStringC{}, "a1", "a01", -1},
+		{"more leading zeros later", NaturalStringC{}, "a001", "a01", 1},
+		{"zeros only", NaturalStringC{}, "0", "00", -1},
+		{"value beats leading zeros", NaturalStringC{}, "a002", "a1", 1},
+		{"text beats leading zeros", NaturalStringC{}, "a01b", "a1c", -1},
+		{"first zeros difference decides", NaturalStringC{}, "a01-1", "a1-01", 1},
+		{"digit vs punctuation", NaturalStringC{}, "a-1", "a1", -1},
+		{"digit vs letter", NaturalStringC{}, "a1", "ab", -1},
+		{"case sensitive", NaturalStringC{}, "B1", "a1", -1},
+		{"case sensitive equal numbers", NaturalStringC{}, "a1", "A1", 1},
+		{"ignore case", NaturalStringC{IgnoreCase: true}, "B1", "a1", 1},
+		{"ignore case equal", NaturalStringC{IgnoreCase: true}, "File10.TXT", "file10.txt", 0},
+		{"ignore case numbers", NaturalStringC{IgnoreCase: true}, "IMG2", "img10", -1},
+		{"ignore case non-ASCII", NaturalStringC{IgnoreCase: true}, "ÄRGER2", "ärger10", -1},
+		{"ignore case leading zeros", NaturalStringC{IgnoreCase: true}, "A01", "a1", 1},
+		{"non-ASCII digits are text", NaturalStringC{}, "٢", "١٠", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if result := tt.comp.Compare(tt.a, tt.b); result != tt.expected {
+				t.Errorf("%+v.Compare(%q, %q) = %d, want %d", tt.comp, tt.a, tt.b, result, tt.expected)
+			}
+			if result := tt.comp.Compare(tt.b, tt.a); result != -tt.expected {
+				t.Errorf("%+v.Compare(%q, %q) = %d, want %d", tt.comp, tt.b, tt.a, result, -tt.expected)
+			}
+		})
+	}
+}
+
+func TestNaturalStringComparatorSort(t *testing.T) {
+	data := []string{"img12.png", "img10.png", "IMG2.png", "img2.png", "img1.png", "img02.png", "img.png", "img100.png"}
+
+	tests := []struct {
+		name     string
+		comp     NaturalStringC
+		expected []string
+	}{
+		{"case sensitive", NaturalStringC{}, []string{"IMG2.png", "img.png", "img1.png", "img2.png", "img02.png", "img10.png", "img12.png", "img100.png"}},
+		{"ignore case", NaturalStringC{IgnoreCase: true}, []string{"img.png", "img1.png", "IMG2.png", "img2.png", "img02.png", "img10.png", "img12.png", "img100.png"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sorted := slices.Clone(data)
+			slices.SortStableFunc(sorted, tt.comp.Compare)
+			if !slices.Equal(sorted, tt.expected) {
+				t.Errorf("Sorted = %q, want %q", sorted, tt.expected)
+			}
+		})
+	}
+}
+
+func TestNaturalStringComparatorContract(t *testing.T) {
+	data := []string{
+		"", "0", "00", "1", "01", "001", "10", "a", "A", "a0", "a00", "a1", "a01", "A1",
+		"a-1", "a1-1", "a01-1", "a1-01", "a1b", "a01b", "ab", "b", "1a", "01a", "x9y", "x09y", "x10",
+		"é", "É1", "\xff", "2a01", "2a1",
+	}
+
+	for _, comp := range []NaturalStringC{{}, {IgnoreCase: true}} {
+		if err := Validate[string](comp, data); err != nil {
+			t.Errorf("%+v: %v", comp, err)
+		}
+	}
+}
+
+func BenchmarkNaturalStringComparator(b *testing.B) {
+	comp := NaturalStringC{}
+	for i := 0; i < b.N; i++ {
+		comp.Compare("photo_2024_0123_img10.jpeg", "photo_2024_0123_img9.jpeg")
+	}
+}
@@ -0,0 +1,5 @@
+//go:build !race
+
+package comparator
+
+const raceEnabled = false
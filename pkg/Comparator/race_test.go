@@ -0,0 +1,7 @@
+//go:build race
+
+package comparator
+
+// raceEnabled — тесты собраны с детектором гонок, при котором sync.Pool
+// случайно отбрасывает объекты и проверки выделений памяти неприменимы
+const raceEnabled = true
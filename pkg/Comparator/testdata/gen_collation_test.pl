@@ -0,0 +1,103 @@
+#!/usr/bin/perl
+# Генерирует collation_test.txt.gz в формате CollationTest из UCA:
+# строки упорядочены по DUCET 13.0.0 (non-ignorable, три уровня),
+# после ";" — результаты сравнения с предыдущей строкой отдельно
+# на первом, втором и третьем уровнях и на третьем уровне без учёта
+# элементов без первичного веса (диакритики).
+# Эталон — Unicode::Collate из поставки Perl (UCA 13.0.0).
+#
+#   perl gen_collation_test.pl ../ucadata/allkeys.txt.gz | gzip -9n > collation_test.txt.gz
+use strict;
+use warnings;
+use Unicode::Collate;
+
+my $allkeys = shift or die "usage: $0 allkeys.txt.gz\n";
+open my $in, '-|', 'gzip', '-dc', $allkeys or die "$allkeys: $!\n";
+
+my $entry = do { local $/; <$in> };
+my ($version) = $entry =~ /^\@version (\S+)/m;
+my $collator = Unicode::Collate->new(
+    table          => undef,
+    entry          => $entry,
+    variable       => 'non-ignorable',
+    normalization  => 'NFD',
+    level          => 3,
+    UCA_Version    => 43,
+);
+
+# Тот же порядок, но третьи веса элементов без первичного веса обнулены
+(my $caseEntry = $entry) =~ s/\[([.*])0000\.([0-9A-F]{4})\.[0-9A-F]{4}\]/[${1}0000.$2.0000]/g;
+my $caseCollator = Unicode::Collate->new(
+    table          => undef,
+    entry          => $caseEntry,
+    variable       => 'non-ignorable',
+    normalization  => 'NFD',
+    level          => 3,
+    UCA_Version    => 43,
+);
+
+my %seen;
+my @strings;
+sub add { my $s = join '', map { chr } @_; push @strings, $s unless $seen{$s}++ }
+
+# Каждый элемент таблицы отдельно и с последующими буквами
+open $in, '-|', 'gzip', '-dc', $allkeys or die "$allkeys: $!\n";
+while (<$in>) {
+    next unless /^([0-9A-F ]+?)\s*;/;
+    my @cps = map { hex } split ' ', $1;
+    add(@cps, @$_) for [], [0x61], [0x41], [0x62];
+}
+
+# Неявные веса: идеограммы, тангутское письмо, нушу, неназначенные символы
+for my $range ([0x3400, 0x4DBF], [0x4E00, 0x9FFC], [0xFA0E, 0xFA2F], [0x20000, 0x2A6DF],
+               [0x2B734, 0x2B741], [0x30000, 0x3134B], [0x17000, 0x18AFF], [0x18D00, 0x18D8F],
+               [0x1B170, 0x1B2FF], [0x18B00, 0x18CFF], [0xE0000, 0xE0002], [0x10FFFD, 0x10FFFF]) {
+    my ($lo, $hi) = @$range;
+    for (my $cp = $lo; $cp <= $hi; $cp += 97) { add($cp); add($cp, 0x61) }
+    add($lo); add($hi); add($hi + 1);
+}
+
+# Слоги хангыля
+for (my $cp = 0xAC00; $cp <= 0xD7A3; $cp += 37) { add($cp); add($cp, 0x1161) }
+
+# Переупорядочение диакритики и разрывные сокращения
+srand 15;
+my @bases = (0x41, 0x61, 0x4C, 0x6C, 0x6F, 0x55, 0x418, 0x438, 0x415, 0x627, 0x0FB2, 0x0FB3, 0x0E40, 0x1B05, 0x3B1);
+my @marks = (0x300, 0x301, 0x302, 0x306, 0x308, 0x30A, 0x323, 0x327, 0x328, 0x31B, 0x334, 0x340,
+             0x653, 0x654, 0x655, 0xF71, 0xF72, 0xF80, 0xF81, 0xE38, 0x1B35, 0x345);
+for (1 .. 4000) {
+    my @s = ($bases[rand @bases]);
+    push @s, $marks[rand @marks] for 1 .. 1 + int rand 3;
+    push @s, (0x61, 0x62, 0x41)[rand 3] if rand() < 0.3;
+    add(@s);
+}
+
+my %key = map { $_ => $collator->getSortKey($_) } @strings;
+my %caseKey = map { $_ => $caseCollator->getSortKey($_) } @strings;
+my @sorted = sort { $key{$a} cmp $key{$b} or $a cmp $b } @strings;
+
+# Веса уровней ключа, разделённых 0000
+sub levels {
+    my @w = unpack 'n*', shift;
+    my @levels = ([]);
+    for (@w) { if ($_ == 0) { push @levels, [] } else { push @{ $levels[-1] }, $_ } }
+    return @levels;
+}
+
+sub relation {
+    my ($x, $y) = @_;
+    for my $i (0 .. $#$x) {
+        return '>' if $i > $#$y;
+        return $x->[$i] < $y->[$i] ? '<' : '>' if $x->[$i] != $y->[$i];
+    }
+    return @$x < @$y ? '<' : '=';
+}
+
+print "# Сгенерировано gen_collation_test.pl, Unicode::Collate $Unicode::Collate::VERSION, DUCET $version\n";
+my @prev;
+for my $s (@sorted) {
+    my @cur = ((levels($key{$s}))[0 .. 2], (levels($caseKey{$s}))[2]);
+    my $rel = @prev ? join(' ', map { relation($prev[$_], $cur[$_]) } 0 .. 3) : '= = = =';
+    printf "%s; %s\n", join(' ', map { sprintf '%04X', ord } split //, $s), $rel;
+    @prev = @cur;
+}
@@ -0,0 +1,25 @@
+#!/usr/bin/perl
+# Выводит данные для NFD: для каждого символа Unicode 13.0 с ненулевым классом
+# канонического комбинирования или с канонической декомпозицией — строку
+# "код;класс;полная декомпозиция". Слоги хангыля раскладываются алгоритмически
+# и не выводятся.
+#
+#   perl gen_nfd.pl | gzip -9n > nfd.txt.gz
+use strict;
+use warnings;
+use Unicode::Normalize qw(getCombinClass getCanon);
+
+for my $cp (0 .. 0x10FFFF) {
+    next if $cp >= 0xD800 && $cp <= 0xDFFF;
+    next if $cp >= 0xAC00 && $cp <= 0xD7A3;
+    next unless chr($cp) =~ /\p{Present_In: 13.0}/;
+
+    my $ccc = getCombinClass($cp);
+    my $canon = getCanon($cp);
+    my $decomp = defined $canon && $canon ne chr($cp)
+        ? join(' ', map { sprintf '%04X', ord } split //, $canon)
+        : '';
+    next if $ccc == 0 && $decomp eq '';
+
+    printf "%04X;%d;%s\n", $cp, $ccc, $decomp;
+}
@@ -0,0 +1,225 @@
+package comparator
+
+import (
+	"fmt"
+	"iter"
+	"math/rand/v2"
+	"strings"
+	"sync"
+)
+
+// Rule — свойство из контракта Comparator. Как и в slices.SortFunc, важен только
+// знак результата Compare, поэтому свойства проверяются на знаках
+type Rule int
+
+const (
+	// Reflexivity — Compare(a, a) = 0
+	Reflexivity Rule = iota
+	// Antisymmetry — знак Compare(a, b) противоположен знаку Compare(b, a)
+	Antisymmetry
+	// Transitivity — из a <= b и b <= c следует a <= c, а из a = b и b = c следует a = c
+	Transitivity
+)
+
+func (r Rule) String() string {
+	switch r {
+	case Reflexivity:
+		return "reflexivity"
+	case Antisymmetry:
+		return "antisymmetry"
+	case Transitivity:
+		return "transitivity"
+	}
+	return fmt.Sprintf("Rule(%d)", int(r))
+}
+
+// Comparison — результат Compare(Elements[A], Elements[B]) в контрпримере
+type Comparison struct {
+	A, B   int
+	Result int
+}
+
+// Violation — нарушение контракта компаратора с контрпримером:
+// элементами и результатами их сравнений
+type Violation[T any] struct {
+	Rule        Rule
+	Elements    []T
+	Comparisons []Comparison
+}
+
+func (v Violation[T]) Error() string {
+	names := []string{"a", "b", "c"}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "comparator: %s violated:", v.Rule)
+	for i, c := range v.Comparisons {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, " Compare(%s, %s) = %d", names[c.A], names[c.B], c.Result)
+	}
+	sb.WriteString(" where")
+	for i, e := range v.Elements {
+		if i > 0 {
+			sb.WriteString(",")
+		}
+		fmt.Fprintf(&sb, " %s = %v", names[i], e)
+	}
+	return sb.String()
+}
+
+// Validate проверяет контракт c на всех парах и тройках элементов data
+// и возвращает первое найденное нарушение или nil
+func Validate[T any](c Comparator[T], data []T) error {
+	for v := range Violations(c, data) {
+		return v
+	}
+	return nil
+}
+
+// Violations перебирает нарушения контракта c на всех парах и тройках элементов data.
+// Каждая пара сравнивается один раз, но проверка троек занимает O(n³),
+// поэтому для больших наборов данных подходит SampleViolations
+func Violations[T any](c Comparator[T], data []T) iter.Seq[Violation[T]] {
+	return func(yield func(Violation[T]) bool) {
+		newMatrix(c, data).violations(yield)
+	}
+}
+
+// SampleViolations перебирает нарушения контракта c на samples случайных тройках
+// элементов data, выбранных с помощью rng
+func SampleViolations[T any](c Comparator[T], data []T, samples int, rng *rand.Rand) iter.Seq[Violation[T]] {
+	return func(yield func(Violation[T]) bool) {
+		if len(data) == 0 {
+			return
+		}
+		for range samples {
+			triple := []T{data[rng.IntN(len(data))], data[rng.IntN(len(data))], data[rng.IntN(len(data))]}
+			if !newMatrix(c, triple).violations(yield) {
+				return
+			}
+		}
+	}
+}
+
+// Checked возвращает компаратор, который проверяет контракт c при каждом сравнении
+// и паникует с Violation при первом нарушении. Пара сравниваемых элементов
+// проверяется вместе с несколькими недавно сравнивавшимися, поэтому каждый вызов
+// обходится в несколько десятков вызовов c. Безопасен для использования из нескольких горутин
+func Checked[T any](c Comparator[T]) Comparator[T] {
+	return &checkedC[T]{c: c}
+}
+
+// checkedHistory — сколько недавно сравнивавшихся элементов участвует в проверке транзитивности
+const checkedHistory = 3
+
+type checkedC[T any] struct {
+	c Comparator[T]
+
+	mu     sync.Mutex
+	recent []T
+	calls  int
+}
+
+func (ch *checkedC[T]) Compare(a, b T) int {
+	ch.mu.Lock()
+	elements := append([]T{a, b}, ch.recent...)
+
+	// Запоминаем по очереди то a, то b, вытесняя самый старый элемент истории
+	next := a
+	if ch.calls%2 == 1 {
+		next = b
+	}
+	if len(ch.recent) < checkedHistory {
+		ch.recent = append(ch.recent, next)
+	} else {
+		ch.recent[ch.calls%checkedHistory] = next
+	}
+	ch.calls++
+	ch.mu.Unlock()
+
+	m := newMatrix(ch.c, elements)
+	m.violations(func(v Violation[T]) bool { panic(v) })
+	return m.result(0, 1)
+}
+
+// matrix — результаты сравнений всех упорядоченных пар элементов
+type matrix[T any] struct {
+	elements []T
+	results  []int
+}
+
+func newMatrix[T any](c Comparator[T], elements []T) matrix[T] {
+	n := len(elements)
+	m := matrix[T]{elements: elements, results: make([]int, n*n)}
+	for i := range elements {
+		for j := range elements {
+			m.results[i*n+j] = c.Compare(elements[i], elements[j])
+		}
+	}
+	return m
+}
+
+func (m matrix[T]) result(i, j int) int {
+	return m.results[i*len(m.elements)+j]
+}
+
+// sign приводит результат Compare к -1, 0 или 1
+func (m matrix[T]) sign(i, j int) int {
+	return max(-1, min(1, m.result(i, j)))
+}
+
+// violation составляет контрпример из элементов indices и сравнений pairs,
+// где пары заданы позициями в indices
+func (m matrix[T]) violation(rule Rule, indices []int, pairs ...[2]int) Violation[T] {
+	v := Violation[T]{Rule: rule}
+	for _, i := range indices {
+		v.Elements = append(v.Elements, m.elements[i])
+	}
+	for _, p := range pairs {
+		v.Comparisons = append(v.Comparisons, Comparison{p[0], p[1], m.result(indices[p[0]], indices[p[1]])})
+	}
+	return v
+}
+
+// violations передаёт yield все нарушения контракта и возвращает false,
+// если yield прервал перебор
+func (m matrix[T]) violations(yield func(Violation[T]) bool) bool {
+	n := len(m.elements)
+
+	for i := range n {
+		if m.result(i, i) != 0 {
+			if !yield(m.violation(Reflexivity, []int{i}, [2]int{0, 0})) {
+				return false
+			}
+		}
+	}
+
+	for i := range n {
+		for j := i + 1; j < n; j++ {
+			if m.sign(i, j) != -m.sign(j, i) {
+				if !yield(m.violation(Antisymmetry, []int{i, j}, [2]int{0, 1}, [2]int{1, 0})) {
+					return false
+				}
+			}
+		}
+	}
+
+	for i := range n {
+		for j := range n {
+			for k := range n {
+				if i == j || j == k || i == k {
+					continue
+				}
+				ab, bc, ac := m.sign(i, j), m.sign(j, k), m.sign(i, k)
+				if (ab <= 0 && bc <= 0 && ac > 0) || (ab == 0 && bc == 0 && ac != 0) {
+					if !yield(m.violation(Transitivity, []int{i, j, k}, [2]int{0, 1}, [2]int{1, 2}, [2]int{0, 2})) {
+						return false
+					}
+				}
+			}
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,213 @@
+package comparator
+
+import (
+	"errors"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// Компараторы с типичными ошибками
+var (
+	// Вычитание переполняется на больших по модулю числах
+	subtractC = ComparatorFunc[int32](func(a, b int32) int { return int(a - b) })
+	// Обычный < не упорядочивает NaN
+	naiveFloatC = ComparatorFunc[float64](func(a, b float64) int {
+		if a < b {
+			return -1
+		}
+		if a > b {
+			return 1
+		}
+		return 0
+	})
+	// Никогда не возвращает 0
+	lessOrGreaterC = ComparatorFunc[int](func(a, b int) int {
+		if a < b {
+			return -1
+		}
+		return 1
+	})
+	// Всегда считает первый аргумент меньшим
+	firstLessC = ComparatorFunc[int](func(a, b int) int { return -1 })
+	// Камень-ножницы-бумага: каждый побеждает следующего
+	rockPaperScissorsC = ComparatorFunc[int](func(a, b int) int {
+		switch {
+		case a == b:
+			return 0
+		case (a+1)%3 == b:
+			return 1
+		}
+		return -1
+	})
+	// Разность, а не знак: допустимо, важен только знак
+	rawDifferenceC = ComparatorFunc[int](func(a, b int) int { return a - b })
+)
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name     string
+		validate func() error
+		rule     Rule // -1, если нарушений нет
+	}{
+		{"IntC", func() error { return Validate[int](IntC{}, []int{3, 1, 2, 1, -5}) }, -1},
+		{"StringC", func() error { return Validate[string](StringC{}, []string{"b", "a", "", "a"}) }, -1},
+		{"OrderedC with NaN", func() error {
+			return Validate[float64](OrderedC[float64]{}, []float64{math.NaN(), 1, math.Copysign(0, -1), 0, math.NaN()})
+		}, -1},
+		{"Empty data", func() error { return Validate[int](lessOrGreaterC, nil) }, -1},
+		{"Raw difference", func() error { return Validate[int](rawDifferenceC, []int{1, 5, -3, 5}) }, -1},
+		{"Never equal", func() error { return Validate[int](lessOrGreaterC, []int{1, 2}) }, Reflexivity},
+		{"First is less", func() error { return Validate[int](firstLessC, []int{1, 1}) }, Reflexivity},
+		{"Naive float with NaN", func() error { return Validate[float64](naiveFloatC, []float64{1, math.NaN(), 2}) }, Transitivity},
+		{"Rock paper scissors", func() error { return Validate[int](rockPaperScissorsC, []int{0, 1, 2}) }, Transitivity},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.validate()
+			if tt.rule < 0 {
+				if err != nil {
+					t.Errorf("Validate() = %v, want nil", err)
+				}
+				return
+			}
+
+			if err == nil {
+				t.Fatalf("Validate() = nil, want %s violation", tt.rule)
+			}
+			if !strings.Contains(err.Error(), tt.rule.String()+" violated") {
+				t.Errorf("Validate() = %v, want %s violation", err, tt.rule)
+			}
+		})
+	}
+}
+
+func TestViolationsCounterexample(t *testing.T) {
+	// Переполнение в вычитании: 2e9 - (-2e9) < 0 для int32
+	data := []int32{-2_000_000_000, 0, 2_000_000_000}
+
+	var found []Violation[int32]
+	for v := range Violations[int32](subtractC, data) {
+		found = append(found, v)
+	}
+	if len(found) == 0 {
+		t.Fatal("Violations() found nothing")
+	}
+
+	for _, v := range found {
+		if v.Rule != Transitivity {
+			t.Errorf("Unexpected rule %s in %v", v.Rule, v)
+		}
+		// Контрпример воспроизводится на тех же элементах
+		for _, c := range v.Comparisons {
+			if got := subtractC.Compare(v.Elements[c.A], v.Elements[c.B]); got != c.Result {
+				t.Errorf("Comparison %+v doesn't reproduce: got %d", c, got)
+			}
+		}
+	}
+
+	var transitivity *Violation[int32]
+	for i := range found {
+		if found[i].Rule == Transitivity {
+			transitivity = &found[i]
+			break
+		}
+	}
+	if transitivity == nil {
+		t.Fatal("Violations() found no transitivity counterexample")
+	}
+	if len(transitivity.Elements) != 3 || len(transitivity.Comparisons) != 3 {
+		t.Errorf("Transitivity counterexample = %+v, want a triple with three comparisons", transitivity)
+	}
+}
+
+func TestViolationsStop(t *testing.T) {
+	// Перебор прекращается, когда цикл прерван
+	count := 0
+	for range Violations[int](firstLessC, []int{1, 2, 3, 4}) {
+		count++
+		if count == 2 {
+			break
+		}
+	}
+	if count != 2 {
+		t.Errorf("Iterated over %d violations, want 2", count)
+	}
+}
+
+func TestSampleViolations(t *testing.T) {
+	rng := rand.New(rand.NewPCG(1, 2))
+	data := make([]int, 1000)
+	for i := range data {
+		data[i] = i % 3
+	}
+
+	found := false
+	for v := range SampleViolations[int](rockPaperScissorsC, data, 100, rng) {
+		if v.Rule != Transitivity {
+			t.Errorf("Unexpected violation %v", v)
+		}
+		found = true
+		break
+	}
+	if !found {
+		t.Error("SampleViolations() found nothing on a cyclic comparator")
+	}
+
+	for v := range SampleViolations[int](IntC{}, data, 1000, rng) {
+		t.Errorf("SampleViolations(IntC) reported %v", v)
+	}
+}
+
+func TestViolationError(t *testing.T) {
+	v := Violation[int]{
+		Rule:        Transitivity,
+		Elements:    []int{0, 1, 2},
+		Comparisons: []Comparison{{0, 1, -1}, {1, 2, -1}, {0, 2, 1}},
+	}
+	expected := "comparator: transitivity violated: Compare(a, b) = -1, Compare(b, c) = -1, Compare(a, c) = 1 where a = 0, b = 1, c = 2"
+
+	if got := v.Error(); got != expected {
+		t.Errorf("Error() = %q, want %q", got, expected)
+	}
+}
+
+func TestChecked(t *testing.T) {
+	t.Run("valid comparator", func(t *testing.T) {
+		data := []int{5, 3, 9, 1, 3, 7}
+		slices.SortFunc(data, Checked[int](IntC{}).Compare)
+		if !slices.IsSorted(data) {
+			t.Errorf("Sorted with Checked(IntC) = %v", data)
+		}
+	})
+
+	broken := []struct {
+		name string
+		comp Comparator[int]
+		rule Rule
+	}{
+		{"Never equal", lessOrGreaterC, Reflexivity},
+		{"Rock paper scissors", rockPaperScissorsC, Transitivity},
+	}
+
+	for _, tt := range broken {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				var v Violation[int]
+				if err, ok := r.(error); !ok || !errors.As(err, &v) {
+					t.Fatalf("Recovered %v, want Violation", r)
+				}
+				if v.Rule != tt.rule {
+					t.Errorf("Violation rule = %s, want %s", v.Rule, tt.rule)
+				}
+			}()
+
+			data := []int{0, 1, 2, 0, 1, 2, 5}
+			slices.SortFunc(data, Checked(tt.comp).Compare)
+		})
+	}
+}
@@ -0,0 +1,158 @@
+package extsort
+
+import (
+	"bufio"
+	"encoding/binary"
+	"encoding/gob"
+	"io"
+	"unsafe"
+)
+
+// Codec — способ записи значений T во временные файлы и чтения их обратно.
+// Он же используется для чтения входа и записи результата Sort
+type Codec[T any] interface {
+	NewEncoder(w io.Writer) Encoder[T]
+	NewDecoder(r io.Reader) Decoder[T]
+}
+
+// Encoder записывает значения в поток
+type Encoder[T any] interface {
+	Encode(v T) error
+}
+
+// Decoder читает значения из потока; в конце потока Decode возвращает io.EOF
+type Decoder[T any] interface {
+	Decode() (T, error)
+}
+
+// Sizer — кодек, который сам оценивает объём памяти, занимаемой значением.
+// Для остальных кодеков Sort учитывает размер T, а у строк и байтовых срезов — и их длину
+type Sizer[T any] interface {
+	Size(v T) int
+}
+
+// sizeFunc возвращает оценку объёма памяти значения для кодека codec
+func sizeFunc[T any](codec Codec[T]) func(T) int {
+	if s, ok := codec.(Sizer[T]); ok {
+		return s.Size
+	}
+
+	var zero T
+	base := int(unsafe.Sizeof(zero))
+	switch any(zero).(type) {
+	case string, []byte:
+		return func(v T) int {
+			switch x := any(v).(type) {
+			case string:
+				return base + len(x)
+			case []byte:
+				return base + len(x)
+			}
+			return base
+		}
+	}
+	return func(T) int { return base }
+}
+
+// Lines — кодек строк текста, разделённых '\n'. Последняя строка может
+// не заканчиваться '\n'; при записи '\n' добавляется после каждой строки.
+// Сами строки не должны содержать '\n'
+type Lines struct{}
+
+func (Lines) NewEncoder(w io.Writer) Encoder[string] {
+	return lineEncoder{w}
+}
+
+func (Lines) NewDecoder(r io.Reader) Decoder[string] {
+	return lineDecoder{bufio.NewReader(r)}
+}
+
+func (Lines) Size(s string) int {
+	return int(unsafe.Sizeof(s)) + len(s)
+}
+
+type lineEncoder struct {
+	w io.Writer
+}
+
+func (e lineEncoder) Encode(s string) error {
+	if _, err := io.WriteString(e.w, s); err != nil {
+		return err
+	}
+	_, err := io.WriteString(e.w, "\n")
+	return err
+}
+
+type lineDecoder struct {
+	r *bufio.Reader
+}
+
+func (d lineDecoder) Decode() (string, error) {
+	s, err := d.r.ReadString('\n')
+	if err == io.EOF && s != "" {
+		return s, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return s[:len(s)-1], nil
+}
+
+// Gob — кодек произвольных значений, которые умеет кодировать encoding/gob
+type Gob[T any] struct{}
+
+func (Gob[T]) NewEncoder(w io.Writer) Encoder[T] {
+	return gobEncoder[T]{gob.NewEncoder(w)}
+}
+
+func (Gob[T]) NewDecoder(r io.Reader) Decoder[T] {
+	return gobDecoder[T]{gob.NewDecoder(r)}
+}
+
+type gobEncoder[T any] struct {
+	enc *gob.Encoder
+}
+
+func (e gobEncoder[T]) Encode(v T) error {
+	return e.enc.Encode(v)
+}
+
+type gobDecoder[T any] struct {
+	dec *gob.Decoder
+}
+
+func (d gobDecoder[T]) Decode() (T, error) {
+	var v T
+	err := d.dec.Decode(&v)
+	return v, err
+}
+
+// Binary — кодек значений фиксированного размера (чисел, массивов и структур из них)
+// в формате encoding/binary с порядком байтов little-endian. Компактнее и быстрее Gob
+type Binary[T any] struct{}
+
+func (Binary[T]) NewEncoder(w io.Writer) Encoder[T] {
+	return binaryEncoder[T]{w}
+}
+
+func (Binary[T]) NewDecoder(r io.Reader) Decoder[T] {
+	return binaryDecoder[T]{bufio.NewReader(r)}
+}
+
+type binaryEncoder[T any] struct {
+	w io.Writer
+}
+
+func (e binaryEncoder[T]) Encode(v T) error {
+	return binary.Write(e.w, binary.LittleEndian, v)
+}
+
+type binaryDecoder[T any] struct {
+	r *bufio.Reader
+}
+
+func (d binaryDecoder[T]) Decode() (T, error) {
+	var v T
+	err := binary.Read(d.r, binary.LittleEndian, &v)
+	return v, err
+}
@@ -0,0 +1,106 @@
+package extsort
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// roundTrip записывает values кодеком codec и читает их обратно
+func roundTrip[T any](t *testing.T, codec Codec[T], values []T) []T {
+	t.Helper()
+	var buf bytes.Buffer
+	enc := codec.NewEncoder(&buf)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			t.Fatalf("Encode(%v) returned %v", v, err)
+		}
+	}
+	return decodeAll(t, codec.NewDecoder(&buf))
+}
+
+func decodeAll[T any](t *testing.T, dec Decoder[T]) []T {
+	t.Helper()
+	got := []T{}
+	for {
+		v, err := dec.Decode()
+		if err == io.EOF {
+			return got
+		}
+		if err != nil {
+			t.Fatalf("Decode() returned %v", err)
+		}
+		got = append(got, v)
+	}
+}
+
+type point struct {
+	X, Y int32
+	Tag  [4]byte
+}
+
+func TestCodecRoundTrip(t *testing.T) {
+	lines := []string{"b", "", "привет", " a "}
+	if got := roundTrip[string](t, Lines{}, lines); !reflect.DeepEqual(got, lines) {
+		t.Errorf("Lines round trip = %q, want %q", got, lines)
+	}
+
+	records := []record{{"x", 1}, {"", -5}, {"y", 0}}
+	if got := roundTrip[record](t, Gob[record]{}, records); !reflect.DeepEqual(got, records) {
+		t.Errorf("Gob round trip = %v, want %v", got, records)
+	}
+
+	ints := []int64{0, -1, 1 << 62, 42}
+	if got := roundTrip[int64](t, Binary[int64]{}, ints); !reflect.DeepEqual(got, ints) {
+		t.Errorf("Binary round trip = %v, want %v", got, ints)
+	}
+
+	points := []point{{1, 2, [4]byte{'a'}}, {-3, 4, [4]byte{}}}
+	if got := roundTrip[point](t, Binary[point]{}, points); !reflect.DeepEqual(got, points) {
+		t.Errorf("Binary round trip = %v, want %v", got, points)
+	}
+}
+
+func TestLinesDecoder(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected []string
+	}{
+		{"Empty", "", []string{}},
+		{"Single empty line", "\n", []string{""}},
+		{"No trailing newline", "a\nb", []string{"a", "b"}},
+		{"Trailing newline", "a\nb\n", []string{"a", "b"}},
+		{"Carriage returns are kept", "a\r\nb\r\n", []string{"a\r", "b\r"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := decodeAll(t, Lines{}.NewDecoder(bytes.NewBufferString(tt.input)))
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Decode() of %q = %q, want %q", tt.input, got, tt.expected)
+			}
+		})
+	}
+}
+
+func TestBinaryTruncated(t *testing.T) {
+	dec := Binary[int64]{}.NewDecoder(bytes.NewReader([]byte{1, 2, 3}))
+	if _, err := dec.Decode(); !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Errorf("Decode() of truncated input returned %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+}
+
+func TestSizeFunc(t *testing.T) {
+	if got := sizeFunc[string](Lines{})("abc"); got != 16+3 {
+		t.Errorf("Lines size = %d, want 19", got)
+	}
+	if got := sizeFunc[[]byte](Gob[[]byte]{})([]byte("abcd")); got != 24+4 {
+		t.Errorf("[]byte size = %d, want 28", got)
+	}
+	if got := sizeFunc[point](Binary[point]{})(point{}); got != 12 {
+		t.Errorf("point size = %d, want 12", got)
+	}
+}
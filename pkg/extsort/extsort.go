@@ -0,0 +1,243 @@
+// Package extsort сортирует потоки, которые не помещаются в память (внешняя сортировка).
+// Вход читается блоками ограниченного объёма, каждый блок сортируется параллельной
+// быстрой сортировкой из пакета qsort и записывается во временный файл (серию),
+// после чего серии сливаются кучей в один упорядоченный поток
+package extsort
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+	"github.com/leonid-voroshilov/mm-qsort/pkg/qsort"
+)
+
+const (
+	// defaultMemoryLimit — объём блока по умолчанию
+	defaultMemoryLimit = 256 << 20
+	// defaultFanIn — сколько серий сливается за один проход. Если серий больше,
+	// они сливаются в несколько проходов, чтобы не держать открытыми тысячи файлов
+	defaultFanIn = 64
+)
+
+// Option — параметр Sort
+type Option func(*config)
+
+// config — параметры сортировки, собранные из Option
+type config struct {
+	memoryLimit int
+	tempDir     string
+	fanIn       int
+	sortOpts    []qsort.Option
+}
+
+// newConfig возвращает параметры по умолчанию, изменённые opts
+func newConfig(opts []Option) config {
+	c := config{
+		memoryLimit: defaultMemoryLimit,
+		fanIn:       defaultFanIn,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithMemoryLimit задаёт примерный объём памяти в байтах под блок, сортируемый в памяти.
+// Объём записи оценивает Sizer кодека, а если кодек его не реализует — размер T.
+// По умолчанию — 256 МиБ
+func WithMemoryLimit(bytes int) Option {
+	return func(c *config) { c.memoryLimit = bytes }
+}
+
+// WithTempDir задаёт каталог для временных файлов. По умолчанию — os.TempDir()
+func WithTempDir(dir string) Option {
+	return func(c *config) { c.tempDir = dir }
+}
+
+// WithSortOptions задаёт параметры qsort.Sort, которым сортируются блоки,
+// например qsort.WithMaxGoroutines
+func WithSortOptions(opts ...qsort.Option) Option {
+	return func(c *config) { c.sortOpts = opts }
+}
+
+// Sort читает значения из in кодеком codec и записывает их в out в порядке comp.
+// Если вход помещается в один блок, временные файлы не создаются. Временные файлы
+// удаляются и при ошибке. Сортировка неустойчива. Отмена ctx прерывает её
+// с ошибкой ctx.Err(); в out к этому моменту может быть записана часть результата.
+// Паника comp — и при сортировке блоков, и при слиянии серий — возвращается
+// как *qsort.PanicError
+func Sort[T any](ctx context.Context, in io.Reader, out io.Writer, codec Codec[T], comp comparator.Comparator[T], opts ...Option) error {
+	return sortWith(ctx, in, out, codec, comp, newConfig(opts))
+}
+
+func sortWith[T any](ctx context.Context, in io.Reader, out io.Writer, codec Codec[T], comp comparator.Comparator[T], c config) (err error) {
+	s := &sorter[T]{ctx: ctx, codec: codec, comp: comp, c: c}
+	defer func() {
+		// Блоки сортирует qsort, который сам возвращает PanicError,
+		// а слияние вызывает comp в этой горутине
+		if r := recover(); r != nil {
+			err = &qsort.PanicError{Value: r, Stack: debug.Stack()}
+		}
+		if s.dir != "" {
+			if rerr := os.RemoveAll(s.dir); err == nil && rerr != nil {
+				err = fmt.Errorf("extsort: %w", rerr)
+			}
+		}
+	}()
+
+	dec := codec.NewDecoder(in)
+	size := sizeFunc(codec)
+	var runs []string
+	var chunk []T
+	for {
+		var eof bool
+		chunk, eof, err = readChunk(dec, size, c.memoryLimit, chunk[:0])
+		if err != nil {
+			return fmt.Errorf("extsort: reading input: %w", err)
+		}
+		if err := qsort.SortContext(ctx, chunk, comp, c.sortOpts...); err != nil {
+			return err
+		}
+
+		// Весь вход поместился в память
+		if eof && len(runs) == 0 {
+			return s.write(out, func(enc Encoder[T]) error { return encodeAll(enc, chunk) })
+		}
+
+		if len(chunk) > 0 {
+			name, err := s.spill(func(enc Encoder[T]) error { return encodeAll(enc, chunk) })
+			if err != nil {
+				return err
+			}
+			runs = append(runs, name)
+		}
+		if eof {
+			break
+		}
+		clear(chunk)
+	}
+	chunk = nil
+
+	for len(runs) > c.fanIn {
+		if runs, err = s.mergePass(runs); err != nil {
+			return err
+		}
+	}
+	return s.write(out, func(enc Encoder[T]) error { return s.mergeRuns(runs, enc) })
+}
+
+// sorter — состояние одной внешней сортировки
+type sorter[T any] struct {
+	ctx   context.Context
+	codec Codec[T]
+	comp  comparator.Comparator[T]
+	c     config
+	dir   string // каталог серий; создаётся при записи первой из них
+}
+
+// readChunk дочитывает в buf значения, пока их суммарный объём меньше limit.
+// eof сообщает, что вход закончился
+func readChunk[T any](dec Decoder[T], size func(T) int, limit int, buf []T) (_ []T, eof bool, err error) {
+	for used := 0; used < limit || len(buf) == 0; {
+		v, err := dec.Decode()
+		if err == io.EOF {
+			return buf, true, nil
+		}
+		if err != nil {
+			return buf, false, err
+		}
+		buf = append(buf, v)
+		used += size(v)
+	}
+	return buf, false, nil
+}
+
+func encodeAll[T any](enc Encoder[T], data []T) error {
+	for _, v := range data {
+		if err := enc.Encode(v); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// write записывает значения в w через буфер кодеком сортировки
+func (s *sorter[T]) write(w io.Writer, encode func(Encoder[T]) error) error {
+	bw := bufio.NewWriter(w)
+	if err := encode(s.codec.NewEncoder(bw)); err != nil {
+		return fmt.Errorf("extsort: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return fmt.Errorf("extsort: %w", err)
+	}
+	return nil
+}
+
+// spill создаёт временный файл серии, записывает в него значения и возвращает его имя
+func (s *sorter[T]) spill(encode func(Encoder[T]) error) (name string, err error) {
+	if s.dir == "" {
+		if s.dir, err = os.MkdirTemp(s.c.tempDir, "extsort-"); err != nil {
+			return "", fmt.Errorf("extsort: %w", err)
+		}
+	}
+
+	f, err := os.CreateTemp(s.dir, "run-")
+	if err != nil {
+		return "", fmt.Errorf("extsort: %w", err)
+	}
+	defer func() {
+		if cerr := f.Close(); err == nil && cerr != nil {
+			err = fmt.Errorf("extsort: %w", cerr)
+		}
+	}()
+
+	bw := bufio.NewWriter(f)
+	if err := encode(s.codec.NewEncoder(bw)); err != nil {
+		return "", fmt.Errorf("extsort: %w", err)
+	}
+	if err := bw.Flush(); err != nil {
+		return "", fmt.Errorf("extsort: %w", err)
+	}
+	return f.Name(), nil
+}
+
+// mergePass сливает серии группами по fanIn и возвращает новые серии
+func (s *sorter[T]) mergePass(runs []string) ([]string, error) {
+	var next []string
+	for i := 0; i < len(runs); i += s.c.fanIn {
+		group := runs[i:min(i+s.c.fanIn, len(runs))]
+		if len(group) == 1 {
+			next = append(next, group[0])
+			continue
+		}
+
+		name, err := s.spill(func(enc Encoder[T]) error { return s.mergeRuns(group, enc) })
+		if err != nil {
+			return nil, err
+		}
+		for _, r := range group {
+			os.Remove(r)
+		}
+		next = append(next, name)
+	}
+	return next, nil
+}
+
+// mergeRuns сливает серии runs и записывает результат в enc
+func (s *sorter[T]) mergeRuns(runs []string, enc Encoder[T]) error {
+	decs := make([]Decoder[T], len(runs))
+	for i, name := range runs {
+		f, err := os.Open(name)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		decs[i] = s.codec.NewDecoder(bufio.NewReader(f))
+	}
+	return merge(s.ctx, decs, enc, s.comp)
+}
@@ -0,0 +1,263 @@
+package extsort
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+	"github.com/leonid-voroshilov/mm-qsort/pkg/qsort"
+)
+
+func randomInts(n int, seed uint64) []int64 {
+	rng := rand.New(rand.NewPCG(seed, seed))
+	data := make([]int64, n)
+	for i := range data {
+		data[i] = rng.Int64N(1000) - 500
+	}
+	return data
+}
+
+func encodeInts(data []int64) *bytes.Buffer {
+	var buf bytes.Buffer
+	binary.Write(&buf, binary.LittleEndian, data)
+	return &buf
+}
+
+func decodeInts(t *testing.T, buf *bytes.Buffer) []int64 {
+	t.Helper()
+	return decodeAll(t, Binary[int64]{}.NewDecoder(buf))
+}
+
+// checkEmptyDir проверяет, что после сортировки во временном каталоге ничего не осталось
+func checkEmptyDir(t *testing.T, dir string) {
+	t.Helper()
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("temp dir contains %d entries after Sort", len(entries))
+	}
+}
+
+func TestSort(t *testing.T) {
+	comp := comparator.OrderedC[int64]{}
+
+	tests := []struct {
+		name        string
+		size        int
+		memoryLimit int
+		fanIn       int
+	}{
+		{"Empty", 0, 1 << 10, defaultFanIn},
+		{"In memory", 10000, 1 << 20, defaultFanIn},
+		{"Single element per run", 100, 1, defaultFanIn},
+		{"Several runs", 100000, 64 << 10, defaultFanIn},
+		{"Multi-pass merge", 100000, 16 << 10, 3},
+		{"Fan-in of two", 5000, 1 << 10, 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := randomInts(tt.size, uint64(tt.size))
+			expected := slices.Clone(data)
+			slices.Sort(expected)
+			dir := t.TempDir()
+
+			var out bytes.Buffer
+			c := newConfig([]Option{WithMemoryLimit(tt.memoryLimit), WithTempDir(dir)})
+			c.fanIn = tt.fanIn
+			if err := sortWith(context.Background(), encodeInts(data), &out, Codec[int64](Binary[int64]{}), comp, c); err != nil {
+				t.Fatal(err)
+			}
+
+			if got := decodeInts(t, &out); !slices.Equal(got, expected) {
+				t.Error("Sort() result is wrong")
+			}
+			checkEmptyDir(t, dir)
+		})
+	}
+}
+
+func TestSortInMemory(t *testing.T) {
+	// Вход помещается в память, поэтому несуществующий каталог не используется
+	data := randomInts(1000, 1)
+	var out bytes.Buffer
+
+	err := Sort(context.Background(), encodeInts(data), &out, Binary[int64]{}, comparator.OrderedC[int64]{},
+		WithTempDir(filepath.Join(t.TempDir(), "missing")))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := decodeInts(t, &out); !slices.IsSorted(got) || len(got) != len(data) {
+		t.Error("Sort() result is wrong")
+	}
+}
+
+func TestSortLines(t *testing.T) {
+	rng := rand.New(rand.NewPCG(2, 3))
+	lines := make([]string, 20000)
+	for i := range lines {
+		lines[i] = fmt.Sprintf("%x-%d", rng.Uint32(), i)
+	}
+	expected := slices.Clone(lines)
+	slices.Sort(expected)
+	dir := t.TempDir()
+
+	var out strings.Builder
+	err := Sort(context.Background(), strings.NewReader(strings.Join(lines, "\n")), &out, Lines{}, comparator.StringC{},
+		WithMemoryLimit(32<<10), WithTempDir(dir), WithSortOptions(qsort.WithMaxGoroutines(4)))
+
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := out.String(); got != strings.Join(expected, "\n")+"\n" {
+		t.Error("Sort() result is wrong")
+	}
+	checkEmptyDir(t, dir)
+}
+
+func TestSortGob(t *testing.T) {
+	records := make([]record, 3000)
+	for i := range records {
+		records[i] = record{Key: fmt.Sprint(i % 97), Seq: i}
+	}
+	var in bytes.Buffer
+	enc := Gob[record]{}.NewEncoder(&in)
+	for _, r := range records {
+		if err := enc.Encode(r); err != nil {
+			t.Fatal(err)
+		}
+	}
+	comp := comparator.Chain[record](recordC{}, comparator.By(func(r record) int { return r.Seq }, comparator.IntC{}))
+	expected := slices.Clone(records)
+	slices.SortFunc(expected, comparator.Func(comp))
+
+	var out bytes.Buffer
+	if err := Sort(context.Background(), &in, &out, Gob[record]{}, comp, WithMemoryLimit(4<<10), WithTempDir(t.TempDir())); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := decodeAll(t, Gob[record]{}.NewDecoder(&out)); !slices.Equal(got, expected) {
+		t.Error("Sort() result is wrong")
+	}
+}
+
+// failingReader возвращает err после того, как отдаст data
+type failingReader struct {
+	data io.Reader
+	err  error
+}
+
+func (r failingReader) Read(p []byte) (int, error) {
+	n, err := r.data.Read(p)
+	if err == io.EOF {
+		return n, r.err
+	}
+	return n, err
+}
+
+// failingWriter возвращает err при каждой записи
+type failingWriter struct {
+	err error
+}
+
+func (w failingWriter) Write([]byte) (int, error) {
+	return 0, w.err
+}
+
+func TestSortErrors(t *testing.T) {
+	errBroken := errors.New("broken")
+	data := randomInts(50000, 4)
+
+	panicking := comparator.ComparatorFunc[int64](func(a, b int64) int {
+		if a == 499 || b == 499 {
+			panic("boom")
+		}
+		return int(a - b)
+	})
+	// Блоки по 2048 одинаковых значений: при сортировке блоков comp видит
+	// только равные значения, при слиянии — разные
+	var runs []int64
+	for v := range int64(4) {
+		runs = append(runs, slices.Repeat([]int64{3 - v}, 2048)...)
+	}
+	panickingMerge := comparator.ComparatorFunc[int64](func(a, b int64) int {
+		if a != b {
+			panic("merge")
+		}
+		return 0
+	})
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		in    io.Reader
+		out   io.Writer
+		comp  comparator.Comparator[int64]
+		check func(err error) bool
+	}{
+		{"Input error", context.Background(), failingReader{encodeInts(data), errBroken}, io.Discard, comparator.OrderedC[int64]{},
+			func(err error) bool { return errors.Is(err, errBroken) }},
+		{"Truncated input", context.Background(), failingReader{encodeInts(data), io.ErrUnexpectedEOF}, io.Discard, comparator.OrderedC[int64]{},
+			func(err error) bool { return errors.Is(err, io.ErrUnexpectedEOF) }},
+		{"Output error", context.Background(), encodeInts(data), failingWriter{errBroken}, comparator.OrderedC[int64]{},
+			func(err error) bool { return errors.Is(err, errBroken) }},
+		{"Canceled", canceled, encodeInts(data), io.Discard, comparator.OrderedC[int64]{},
+			func(err error) bool { return errors.Is(err, context.Canceled) }},
+		{"Comparator panic", context.Background(), encodeInts(data), io.Discard, panicking,
+			func(err error) bool {
+				var pe *qsort.PanicError
+				return errors.As(err, &pe) && pe.Value == "boom"
+			}},
+		{"Comparator panic in merge", context.Background(), encodeInts(runs), io.Discard, panickingMerge,
+			func(err error) bool {
+				var pe *qsort.PanicError
+				return errors.As(err, &pe) && pe.Value == "merge"
+			}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			err := Sort(tt.ctx, tt.in, tt.out, Binary[int64]{}, tt.comp, WithMemoryLimit(16<<10), WithTempDir(dir))
+
+			if !tt.check(err) {
+				t.Errorf("Sort() returned %v", err)
+			}
+			checkEmptyDir(t, dir)
+		})
+	}
+}
+
+func BenchmarkSort(b *testing.B) {
+	data := randomInts(1000000, 5)
+	input := encodeInts(data).Bytes()
+	comp := comparator.OrderedC[int64]{}
+
+	for _, limit := range []int{64 << 20, 8 << 20, 1 << 20} {
+		b.Run(fmt.Sprintf("memory=%dMiB", limit>>20), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				err := Sort(context.Background(), bytes.NewReader(input), io.Discard, Binary[int64]{}, comp,
+					WithMemoryLimit(limit), WithTempDir(b.TempDir()))
+				if err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
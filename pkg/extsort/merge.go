@@ -0,0 +1,94 @@
+package extsort
+
+import (
+	"context"
+	"io"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// cancelCheckInterval — как часто merge проверяет отмену контекста, в записях
+const cancelCheckInterval = 4096
+
+// head — очередное значение серии src
+type head[T any] struct {
+	v   T
+	src int
+}
+
+// mergeHeap — min-куча голов серий. При равных значениях раньше идёт серия
+// с меньшим номером, так что результат не зависит от устройства кучи
+type mergeHeap[T any] struct {
+	items []head[T]
+	comp  comparator.Comparator[T]
+}
+
+func (h *mergeHeap[T]) less(i, j int) bool {
+	a, b := &h.items[i], &h.items[j]
+	if r := h.comp.Compare(a.v, b.v); r != 0 {
+		return r < 0
+	}
+	return a.src < b.src
+}
+
+func (h *mergeHeap[T]) siftDown(i int) {
+	n := len(h.items)
+	for {
+		child := 2*i + 1
+		if child >= n {
+			return
+		}
+		if child+1 < n && h.less(child+1, child) {
+			child++
+		}
+		if !h.less(child, i) {
+			return
+		}
+		h.items[i], h.items[child] = h.items[child], h.items[i]
+		i = child
+	}
+}
+
+// merge сливает упорядоченные по comp потоки decs в enc
+func merge[T any](ctx context.Context, decs []Decoder[T], enc Encoder[T], comp comparator.Comparator[T]) error {
+	h := &mergeHeap[T]{items: make([]head[T], 0, len(decs)), comp: comp}
+	for i, dec := range decs {
+		v, err := dec.Decode()
+		if err == io.EOF {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		h.items = append(h.items, head[T]{v, i})
+	}
+	for i := len(h.items)/2 - 1; i >= 0; i-- {
+		h.siftDown(i)
+	}
+
+	for n := 0; len(h.items) > 0; n++ {
+		if n%cancelCheckInterval == 0 {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+		}
+
+		top := &h.items[0]
+		if err := enc.Encode(top.v); err != nil {
+			return err
+		}
+		v, err := decs[top.src].Decode()
+		switch {
+		case err == io.EOF:
+			last := len(h.items) - 1
+			h.items[0] = h.items[last]
+			h.items = h.items[:last]
+		case err != nil:
+			return err
+		default:
+			top.v = v
+		}
+		h.siftDown(0)
+	}
+	return nil
+}
@@ -0,0 +1,118 @@
+package extsort
+
+import (
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// record — значение с ключом сортировки и номером, по которому видно, откуда оно
+type record struct {
+	Key string
+	Seq int
+}
+
+type recordC struct{}
+
+func (recordC) Compare(a, b record) int {
+	switch {
+	case a.Key < b.Key:
+		return -1
+	case a.Key > b.Key:
+		return 1
+	}
+	return 0
+}
+
+// sliceDecoder читает значения из среза, а затем возвращает err или io.EOF
+type sliceDecoder[T any] struct {
+	values []T
+	err    error
+}
+
+func (d *sliceDecoder[T]) Decode() (T, error) {
+	if len(d.values) == 0 {
+		var zero T
+		if d.err != nil {
+			return zero, d.err
+		}
+		return zero, io.EOF
+	}
+	v := d.values[0]
+	d.values = d.values[1:]
+	return v, nil
+}
+
+// sliceEncoder дописывает значения в срез
+type sliceEncoder[T any] struct {
+	values []T
+}
+
+func (e *sliceEncoder[T]) Encode(v T) error {
+	e.values = append(e.values, v)
+	return nil
+}
+
+func TestMerge(t *testing.T) {
+	tests := []struct {
+		name     string
+		inputs   [][]record
+		expected []record
+	}{
+		{"No inputs", nil, nil},
+		{"Empty inputs", [][]record{{}, {}}, nil},
+		{"Single input", [][]record{{{"a", 1}, {"b", 2}}}, []record{{"a", 1}, {"b", 2}}},
+		{
+			"Ties in input order",
+			[][]record{
+				{{"a", 10}, {"b", 11}, {"b", 12}},
+				{},
+				{{"a", 30}, {"b", 31}, {"c", 32}},
+				{{"b", 40}},
+			},
+			[]record{{"a", 10}, {"a", 30}, {"b", 11}, {"b", 12}, {"b", 31}, {"b", 40}, {"c", 32}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			decs := make([]Decoder[record], len(tt.inputs))
+			for i, in := range tt.inputs {
+				decs[i] = &sliceDecoder[record]{values: in}
+			}
+			enc := &sliceEncoder[record]{}
+
+			if err := merge(context.Background(), decs, Encoder[record](enc), recordC{}); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(enc.values, tt.expected) {
+				t.Errorf("merge() = %v, want %v", enc.values, tt.expected)
+			}
+		})
+	}
+}
+
+func TestMergeErrors(t *testing.T) {
+	errBroken := errors.New("broken run")
+
+	t.Run("Decode", func(t *testing.T) {
+		decs := []Decoder[record]{
+			&sliceDecoder[record]{values: []record{{"a", 1}, {"c", 2}}},
+			&sliceDecoder[record]{values: []record{{"b", 3}}, err: errBroken},
+		}
+		if err := merge(context.Background(), decs, Encoder[record](&sliceEncoder[record]{}), recordC{}); !errors.Is(err, errBroken) {
+			t.Errorf("merge() returned %v, want %v", err, errBroken)
+		}
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		decs := []Decoder[record]{&sliceDecoder[record]{values: []record{{"a", 1}}}}
+		if err := merge(ctx, decs, Encoder[record](&sliceEncoder[record]{}), recordC{}); !errors.Is(err, context.Canceled) {
+			t.Errorf("merge() returned %v, want %v", err, context.Canceled)
+		}
+	})
+}
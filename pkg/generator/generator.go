@@ -0,0 +1,277 @@
+// Package generator генерирует входные данные для тестов и бенчмарков сортировок:
+// целые и вещественные числа, строки и структуры с разными распределениями.
+// Генератор детерминирован: с одним и тем же seed он выдаёт одни и те же данные
+package generator
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+)
+
+// Generator — источник псевдослучайных входных данных. Не безопасен
+// для одновременного использования из нескольких горутин
+type Generator struct {
+	rng *rand.Rand
+}
+
+// New возвращает генератор с начальным значением seed
+func New(seed uint64) *Generator {
+	return &Generator{rand.New(rand.NewPCG(seed, seed))}
+}
+
+// Distribution — распределение значений во входных данных
+type Distribution struct {
+	name string
+	fill func(r *rand.Rand, data []float64)
+}
+
+// String возвращает название распределения с параметрами, например "Sawtooth(100)";
+// его удобно использовать как имя подтеста
+func (d Distribution) String() string {
+	return d.name
+}
+
+// Uniform — равномерно распределённые значения из [0, limit)
+func Uniform(limit float64) Distribution {
+	return Distribution{fmt.Sprintf("Uniform(%g)", limit), func(r *rand.Rand, data []float64) {
+		for i := range data {
+			data[i] = r.Float64() * limit
+		}
+	}}
+}
+
+// Sorted — возрастающие значения 0, 1, ..., n-1
+func Sorted() Distribution {
+	return Distribution{"Sorted", fillSorted}
+}
+
+func fillSorted(_ *rand.Rand, data []float64) {
+	for i := range data {
+		data[i] = float64(i)
+	}
+}
+
+// Reversed — убывающие значения n-1, ..., 1, 0
+func Reversed() Distribution {
+	return Distribution{"Reversed", func(_ *rand.Rand, data []float64) {
+		for i := range data {
+			data[i] = float64(len(data) - 1 - i)
+		}
+	}}
+}
+
+// NearlySorted — возрастающие значения, в которых swaps раз переставлены
+// два случайных элемента
+func NearlySorted(swaps int) Distribution {
+	return Distribution{fmt.Sprintf("NearlySorted(%d)", swaps), func(r *rand.Rand, data []float64) {
+		fillSorted(r, data)
+		if len(data) == 0 {
+			return
+		}
+		for range swaps {
+			i, j := r.IntN(len(data)), r.IntN(len(data))
+			data[i], data[j] = data[j], data[i]
+		}
+	}}
+}
+
+// OrganPipe — значения возрастают до середины, а затем убывают
+func OrganPipe() Distribution {
+	return Distribution{"OrganPipe", func(_ *rand.Rand, data []float64) {
+		for i := range data {
+			data[i] = float64(min(i, len(data)-1-i))
+		}
+	}}
+}
+
+// Sawtooth — возрастающие отрезки длины period: 0, 1, ..., period-1, 0, 1, ...
+// Период меньше 1 считается равным 1
+func Sawtooth(period int) Distribution {
+	period = max(period, 1)
+	return Distribution{fmt.Sprintf("Sawtooth(%d)", period), func(_ *rand.Rand, data []float64) {
+		for i := range data {
+			data[i] = float64(i % period)
+		}
+	}}
+}
+
+// FewUnique — случайные значения из {0, 1, ..., k-1}. При k меньше 1
+// все значения равны нулю, как при k = 1
+func FewUnique(k int) Distribution {
+	k = max(k, 1)
+	return Distribution{fmt.Sprintf("FewUnique(%d)", k), func(r *rand.Rand, data []float64) {
+		for i := range data {
+			data[i] = float64(r.IntN(k))
+		}
+	}}
+}
+
+// AllEqual — все значения равны нулю
+func AllEqual() Distribution {
+	return Distribution{"AllEqual", func(_ *rand.Rand, data []float64) {
+		clear(data)
+	}}
+}
+
+// Zipf — целые значения из [0, limit] с распределением Ципфа: значение k
+// встречается с вероятностью, пропорциональной (1+k)^-s. При s > 1 значения
+// выбирает rand.Zipf, а при s <= 1, когда он неприменим, — непрерывное
+// приближение: округлённые вниз значения с плотностью (1+x)^-s на [0, limit+1)
+func Zipf(s float64, limit uint64) Distribution {
+	return Distribution{fmt.Sprintf("Zipf(%g,%d)", s, limit), func(r *rand.Rand, data []float64) {
+		if s > 1 {
+			z := rand.NewZipf(r, s, 1, limit)
+			for i := range data {
+				data[i] = float64(z.Uint64())
+			}
+			return
+		}
+
+		// Обращение функции распределения ∫(1+x)^-s dx на [0, limit+1)
+		end := float64(limit) + 2
+		for i := range data {
+			u := r.Float64()
+			var x float64
+			if s == 1 {
+				x = math.Pow(end, u)
+			} else {
+				q := 1 - s
+				x = math.Pow(1+u*(math.Pow(end, q)-1), 1/q)
+			}
+			data[i] = min(math.Floor(x-1), float64(limit))
+		}
+	}}
+}
+
+// Gaussian — нормально распределённые значения со средним mean
+// и стандартным отклонением stddev
+func Gaussian(mean, stddev float64) Distribution {
+	return Distribution{fmt.Sprintf("Gaussian(%g,%g)", mean, stddev), func(r *rand.Rand, data []float64) {
+		for i := range data {
+			data[i] = r.NormFloat64()*stddev + mean
+		}
+	}}
+}
+
+// Distributions возвращает по распределению каждого вида с параметрами,
+// подходящими для n элементов, — например, для бенчмарка по всем распределениям
+func Distributions(n int) []Distribution {
+	return []Distribution{
+		Uniform(float64(n)),
+		Sorted(),
+		Reversed(),
+		NearlySorted(max(n/100, 1)),
+		OrganPipe(),
+		Sawtooth(max(n/10, 1)),
+		FewUnique(8),
+		AllEqual(),
+		Zipf(1.2, uint64(n)),
+		Gaussian(0, float64(n/6)),
+	}
+}
+
+// Floats возвращает n значений с распределением d
+func (g *Generator) Floats(d Distribution, n int) []float64 {
+	data := make([]float64, n)
+	d.fill(g.rng, data)
+	return data
+}
+
+// Ints возвращает n значений с распределением d, округлённых вниз
+func (g *Generator) Ints(d Distribution, n int) []int {
+	return Map(g.Floats(d, n), func(v float64) int { return int(math.Floor(v)) })
+}
+
+// Strings возвращает n строк из строчных латинских букв, порядок которых
+// повторяет порядок Ints(d, n): равным числам соответствуют равные строки,
+// а меньшим — лексикографически меньшие. Строки имеют длину length или больше,
+// если length букв не хватает, чтобы различить все значения
+func (g *Generator) Strings(d Distribution, n, length int) []string {
+	ints := g.Ints(d, n)
+	if n == 0 {
+		return []string{}
+	}
+
+	lo, hi := slices.Min(ints), slices.Max(ints)
+	width := max(length, 1)
+	for span := uint64(hi - lo); span >= pow26(width); {
+		width++
+	}
+
+	return Map(ints, func(v int) string {
+		b := make([]byte, width)
+		x := uint64(v - lo)
+		for i := width - 1; i >= 0; i-- {
+			b[i] = 'a' + byte(x%26)
+			x /= 26
+		}
+		return string(b)
+	})
+}
+
+// pow26 возвращает 26^k или math.MaxUint64, если оно не помещается в uint64
+func pow26(k int) uint64 {
+	p := uint64(1)
+	for range k {
+		if p > math.MaxUint64/26 {
+			return math.MaxUint64
+		}
+		p *= 26
+	}
+	return p
+}
+
+// RandomStrings возвращает n случайных строк длиной от minLen до maxLen
+// из символов alphabet; пустой alphabet означает строчные латинские буквы.
+// Отрицательный minLen считается нулём, а maxLen меньше minLen — равным minLen
+func (g *Generator) RandomStrings(n, minLen, maxLen int, alphabet string) []string {
+	if alphabet == "" {
+		alphabet = "abcdefghijklmnopqrstuvwxyz"
+	}
+	minLen = max(minLen, 0)
+	maxLen = max(maxLen, minLen)
+	letters := []rune(alphabet)
+
+	data := make([]string, n)
+	buf := make([]rune, 0, maxLen)
+	for i := range data {
+		buf = buf[:0]
+		for range minLen + g.rng.IntN(maxLen-minLen+1) {
+			buf = append(buf, letters[g.rng.IntN(len(letters))])
+		}
+		data[i] = string(buf)
+	}
+	return data
+}
+
+// Record — запись для сортировки структур: Key распределён по d,
+// Seq — исходная позиция записи, по которой проверяется устойчивость,
+// Name — случайная строка, делающая запись тяжелее
+type Record struct {
+	Key  int
+	Seq  int
+	Name string
+}
+
+// Records возвращает n записей с ключами, распределёнными по d
+func (g *Generator) Records(d Distribution, n int) []Record {
+	keys := g.Ints(d, n)
+	names := g.RandomStrings(n, 4, 12, "")
+	data := make([]Record, n)
+	for i := range data {
+		data[i] = Record{Key: keys[i], Seq: i, Name: names[i]}
+	}
+	return data
+}
+
+// Map применяет f к каждому элементу data — например, чтобы построить
+// из Ints срез структур своего типа
+func Map[S, T any](data []S, f func(S) T) []T {
+	res := make([]T, len(data))
+	for i, v := range data {
+		res[i] = f(v)
+	}
+	return res
+}
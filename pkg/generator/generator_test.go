@@ -0,0 +1,221 @@
+package generator
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+	"unicode/utf8"
+)
+
+func TestDistributions(t *testing.T) {
+	const n = 1000
+
+	tests := []struct {
+		d     Distribution
+		name  string
+		check func(data []int) bool
+	}{
+		{Uniform(50), "Uniform(50)", func(data []int) bool {
+			return slices.Min(data) >= 0 && slices.Max(data) < 50
+		}},
+		{Sorted(), "Sorted", func(data []int) bool {
+			return slices.Equal(data, seq(n))
+		}},
+		{Reversed(), "Reversed", func(data []int) bool {
+			return data[0] == n-1 && slices.IsSortedFunc(data, func(a, b int) int { return b - a })
+		}},
+		{NearlySorted(5), "NearlySorted(5)", func(data []int) bool {
+			misplaced := 0
+			for i, v := range data {
+				if v != i {
+					misplaced++
+				}
+			}
+			return misplaced <= 10 && slices.Equal(slices.Sorted(slices.Values(data)), seq(n))
+		}},
+		{OrganPipe(), "OrganPipe", func(data []int) bool {
+			return slices.IsSorted(data[:n/2]) && data[n/2-1] == n/2-1 && data[n-1] == 0
+		}},
+		{Sawtooth(100), "Sawtooth(100)", func(data []int) bool {
+			return data[99] == 99 && data[100] == 0 && slices.Max(data) == 99
+		}},
+		{FewUnique(4), "FewUnique(4)", func(data []int) bool {
+			return len(slices.Compact(slices.Sorted(slices.Values(data)))) == 4
+		}},
+		{AllEqual(), "AllEqual", func(data []int) bool {
+			return slices.Min(data) == 0 && slices.Max(data) == 0
+		}},
+		{Zipf(1.5, 100), "Zipf(1.5,100)", func(data []int) bool {
+			zeros := 0
+			for _, v := range data {
+				if v == 0 {
+					zeros++
+				}
+			}
+			// Ноль — самое частое значение
+			return slices.Min(data) >= 0 && slices.Max(data) <= 100 && zeros > n/4
+		}},
+		{Zipf(1, 100), "Zipf(1,100)", func(data []int) bool {
+			zeros := 0
+			for _, v := range data {
+				if v == 0 {
+					zeros++
+				}
+			}
+			return slices.Min(data) >= 0 && slices.Max(data) <= 100 && zeros > n/10 && lowerHalf(data, 100) > n/2
+		}},
+		{Zipf(0.5, 100), "Zipf(0.5,100)", func(data []int) bool {
+			return slices.Min(data) >= 0 && slices.Max(data) <= 100 && lowerHalf(data, 100) > n/2
+		}},
+		{Zipf(-1, 0), "Zipf(-1,0)", func(data []int) bool {
+			return slices.Min(data) == 0 && slices.Max(data) == 0
+		}},
+		{Sawtooth(0), "Sawtooth(1)", func(data []int) bool {
+			return slices.Min(data) == 0 && slices.Max(data) == 0
+		}},
+		{FewUnique(-2), "FewUnique(1)", func(data []int) bool {
+			return slices.Min(data) == 0 && slices.Max(data) == 0
+		}},
+		{Gaussian(100, 10), "Gaussian(100,10)", func(data []int) bool {
+			sum := 0
+			for _, v := range data {
+				sum += v
+			}
+			mean := float64(sum) / n
+			return math.Abs(mean-99.5) < 2 && slices.Min(data) > 30 && slices.Max(data) < 170
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.d.String() != tt.name {
+				t.Errorf("String() = %q, want %q", tt.d.String(), tt.name)
+			}
+			data := New(1).Ints(tt.d, n)
+			if len(data) != n || !tt.check(data) {
+				t.Errorf("Ints(%v) = %v...", tt.d, data[:20])
+			}
+			if got := New(1).Ints(tt.d, 0); len(got) != 0 {
+				t.Errorf("Ints(%v, 0) = %v", tt.d, got)
+			}
+		})
+	}
+}
+
+// lowerHalf возвращает, сколько значений data меньше limit/2
+func lowerHalf(data []int, limit int) int {
+	count := 0
+	for _, v := range data {
+		if v < limit/2 {
+			count++
+		}
+	}
+	return count
+}
+
+func seq(n int) []int {
+	data := make([]int, n)
+	for i := range data {
+		data[i] = i
+	}
+	return data
+}
+
+func TestReproducible(t *testing.T) {
+	for _, d := range Distributions(500) {
+		t.Run(d.String(), func(t *testing.T) {
+			if !slices.Equal(New(7).Floats(d, 500), New(7).Floats(d, 500)) {
+				t.Error("generators with the same seed produced different data")
+			}
+		})
+	}
+
+	if slices.Equal(New(1).Ints(Uniform(1e9), 100), New(2).Ints(Uniform(1e9), 100)) {
+		t.Error("generators with different seeds produced the same data")
+	}
+}
+
+func TestStrings(t *testing.T) {
+	g := New(3)
+
+	for _, d := range Distributions(2000) {
+		t.Run(d.String(), func(t *testing.T) {
+			// Порядок строк должен повторять порядок чисел того же генератора
+			ints := New(4).Ints(d, 2000)
+			strs := New(4).Strings(d, 2000, 3)
+
+			for i := 1; i < len(ints); i++ {
+				if cmpInt(ints[i-1], ints[i]) != strings.Compare(strs[i-1], strs[i]) {
+					t.Fatalf("order of %q, %q differs from %d, %d", strs[i-1], strs[i], ints[i-1], ints[i])
+				}
+			}
+			for _, s := range strs {
+				if len(s) < 3 || len(s) != len(strs[0]) {
+					t.Fatalf("string %q has wrong length", s)
+				}
+			}
+		})
+	}
+
+	// Двух букв не хватает на 1000 значений, и строки удлиняются
+	if s := g.Strings(Sorted(), 1000, 2); len(s[0]) != 3 || s[0] != "aaa" || s[999] != "bml" {
+		t.Errorf("Strings(Sorted, 1000, 2) = %q ... %q", s[0], s[999])
+	}
+	if s := g.Strings(Sorted(), 0, 2); len(s) != 0 {
+		t.Errorf("Strings(Sorted, 0, 2) = %q", s)
+	}
+}
+
+func cmpInt(a, b int) int {
+	switch {
+	case a < b:
+		return -1
+	case a > b:
+		return 1
+	}
+	return 0
+}
+
+func TestRandomStrings(t *testing.T) {
+	g := New(5)
+
+	for _, s := range g.RandomStrings(1000, 2, 5, "") {
+		if len(s) < 2 || len(s) > 5 || strings.Trim(s, "abcdefghijklmnopqrstuvwxyz") != "" {
+			t.Fatalf("RandomStrings() produced %q", s)
+		}
+	}
+	for _, s := range g.RandomStrings(100, 3, 3, "αβγ") {
+		if utf8.RuneCountInString(s) != 3 || strings.Trim(s, "αβγ") != "" {
+			t.Fatalf("RandomStrings() with alphabet produced %q", s)
+		}
+	}
+
+	// Длины вне допустимого диапазона приводятся к нему
+	for _, s := range g.RandomStrings(100, 4, 1, "") {
+		if len(s) != 4 {
+			t.Fatalf("RandomStrings(maxLen < minLen) produced %q", s)
+		}
+	}
+	for _, s := range g.RandomStrings(100, -3, 1, "") {
+		if len(s) > 1 {
+			t.Fatalf("RandomStrings(negative minLen) produced %q", s)
+		}
+	}
+}
+
+func TestRecords(t *testing.T) {
+	data := New(6).Records(FewUnique(3), 100)
+
+	for i, r := range data {
+		if r.Seq != i || r.Key < 0 || r.Key >= 3 || r.Name == "" {
+			t.Fatalf("record %d = %+v", i, r)
+		}
+	}
+
+	type pair struct{ a, b int }
+	pairs := Map(seq(3), func(v int) pair { return pair{v, -v} })
+	if !slices.Equal(pairs, []pair{{0, 0}, {1, -1}, {2, -2}}) {
+		t.Errorf("Map() = %v", pairs)
+	}
+}
@@ -0,0 +1,102 @@
+package qsort
+
+import (
+	"fmt"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// indexC сравнивает индексы по элементам data, а равные элементы — по индексу
+type indexC[T any] struct {
+	data []T
+	comp comparator.Comparator[T]
+}
+
+func (c indexC[T]) Compare(i, j int) int {
+	if r := c.comp.Compare(c.data[i], c.data[j]); r != 0 {
+		return r
+	}
+	if i < j {
+		return -1
+	}
+	if i > j {
+		return 1
+	}
+	return 0
+}
+
+// ArgSort возвращает перестановку, упорядочивающую data, не меняя data:
+// data[perm[0]] <= data[perm[1]] <= ... Индексы равных элементов идут по возрастанию.
+// Применить перестановку к data и к другим срезам той же длины можно ApplyPermutation
+func ArgSort[T any](data []T, comp comparator.Comparator[T]) []int {
+	return ParallelArgSort(data, comp, WithMaxGoroutines(1))
+}
+
+// ParallelArgSort — параллельный ArgSort: индексы сортируются Sort с параметрами opts
+func ParallelArgSort[T any](data []T, comp comparator.Comparator[T], opts ...Option) []int {
+	perm := make([]int, len(data))
+	for i := range perm {
+		perm[i] = i
+	}
+	Sort(perm, indexC[T]{data, comp}, opts...)
+	return perm
+}
+
+// ApplyPermutation переставляет data на месте так, что новый data[i] — прежний
+// data[perm[i]], например ApplyPermutation(data, ArgSort(data, comp)) сортирует data.
+// Перестановка применяется обходом её циклов: каждый элемент перемещается один раз,
+// дополнительная память — n бит. perm не меняется, поэтому одну перестановку можно
+// применить к нескольким срезам, в том числе одновременно. Паникует, если perm
+// не перестановка индексов data
+func ApplyPermutation[T any](data []T, perm []int) {
+	if len(perm) != len(data) {
+		panic(fmt.Errorf("qsort: permutation length %d doesn't match data length %d", len(perm), len(data)))
+	}
+
+	done := newBitset(len(perm))
+	for i, p := range perm {
+		if p < 0 || p >= len(perm) {
+			panic(fmt.Errorf("qsort: perm[%d] = %d is out of range [0, %d)", i, p, len(perm)))
+		}
+		if done.get(p) {
+			panic(fmt.Errorf("qsort: perm[%d] = %d is repeated", i, p))
+		}
+		done.set(p)
+	}
+	done.clear()
+
+	for i := range data {
+		if done.get(i) {
+			continue
+		}
+		first := data[i]
+		j := i
+		for perm[j] != i {
+			next := perm[j]
+			data[j] = data[next]
+			done.set(j)
+			j = next
+		}
+		data[j] = first
+		done.set(j)
+	}
+}
+
+// bitset — множество индексов от 0 до n-1
+type bitset []uint64
+
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+func (b bitset) get(i int) bool {
+	return b[i/64]&(1<<(i%64)) != 0
+}
+
+func (b bitset) set(i int) {
+	b[i/64] |= 1 << (i % 64)
+}
+
+func (b bitset) clear() {
+	clear(b)
+}
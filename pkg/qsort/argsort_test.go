@@ -0,0 +1,168 @@
+package qsort
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+func TestArgSort(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	tests := []struct {
+		name     string
+		data     []int
+		expected []int
+	}{
+		{"Empty slice", []int{}, []int{}},
+		{"Single element", []int{42}, []int{0}},
+		{"Sorted", []int{1, 2, 3}, []int{0, 1, 2}},
+		{"Reversed", []int{3, 2, 1}, []int{2, 1, 0}},
+		{"Duplicates keep index order", []int{2, 1, 2, 1, 0}, []int{4, 1, 3, 0, 2}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+
+			perm := ArgSort(data, comp)
+
+			if !reflect.DeepEqual(perm, tt.expected) {
+				t.Errorf("ArgSort(%v) = %v, want %v", tt.data, perm, tt.expected)
+			}
+			if !reflect.DeepEqual(data, tt.data) {
+				t.Errorf("ArgSort changed data to %v", data)
+			}
+		})
+	}
+}
+
+func TestParallelArgSort(t *testing.T) {
+	data := generateRecords(100000, 1000)
+	original := copySlice(data)
+	expected := stableSorted(data)
+
+	perm := ParallelArgSort(data, recordComparator{}, WithMaxGoroutines(8))
+
+	if !reflect.DeepEqual(data, original) {
+		t.Fatal("ParallelArgSort changed data")
+	}
+	sorted := make([]record, len(data))
+	for i, p := range perm {
+		sorted[i] = data[p]
+	}
+	if !reflect.DeepEqual(sorted, expected) {
+		t.Error("data[perm[i]] differs from stable sort")
+	}
+}
+
+func TestApplyPermutation(t *testing.T) {
+	tests := []struct {
+		name string
+		perm []int
+	}{
+		{"Empty", []int{}},
+		{"Identity", []int{0, 1, 2, 3}},
+		{"Swap", []int{1, 0}},
+		{"Single cycle", []int{1, 2, 3, 4, 0}},
+		{"Reversed", []int{4, 3, 2, 1, 0}},
+		{"Several cycles", []int{2, 0, 1, 3, 5, 4}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := make([]string, len(tt.perm))
+			expected := make([]string, len(tt.perm))
+			for i, p := range tt.perm {
+				data[i] = fmt.Sprint("x", i)
+				expected[i] = fmt.Sprint("x", p)
+			}
+			perm := copySlice(tt.perm)
+
+			ApplyPermutation(data, perm)
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Errorf("ApplyPermutation(%v) = %v, want %v", tt.perm, data, expected)
+			}
+			if !reflect.DeepEqual(perm, tt.perm) {
+				t.Errorf("ApplyPermutation changed perm to %v", perm)
+			}
+		})
+	}
+}
+
+func TestApplyPermutationInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		perm    []int
+		message string
+	}{
+		{"Length mismatch", []int{0, 1}, "doesn't match data length"},
+		{"Out of range", []int{0, 3, 1}, "perm[1] = 3 is out of range"},
+		{"Negative", []int{0, -1, 1}, "perm[1] = -1 is out of range"},
+		{"Repeated", []int{2, 0, 2}, "perm[2] = 2 is repeated"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := []int{10, 20, 30}
+			defer func() {
+				err, ok := recover().(error)
+				if !ok || !strings.Contains(err.Error(), tt.message) {
+					t.Errorf("Recovered %v, want error containing %q", err, tt.message)
+				}
+				if !reflect.DeepEqual(data, []int{10, 20, 30}) {
+					t.Errorf("data changed to %v", data)
+				}
+			}()
+			ApplyPermutation(data, tt.perm)
+		})
+	}
+}
+
+func TestApplyPermutationColumns(t *testing.T) {
+	// Таблица из трёх столбцов, упорядоченная по столбцу ages
+	names := []string{"carol", "alice", "dave", "bob", "erin"}
+	ages := []int{35, 30, 41, 35, 30}
+	ids := []int{1, 2, 3, 4, 5}
+
+	perm := ArgSort(ages, comparator.OrderedC[int]{})
+
+	var wg sync.WaitGroup
+	wg.Add(3)
+	go func() { defer wg.Done(); ApplyPermutation(names, perm) }()
+	go func() { defer wg.Done(); ApplyPermutation(ages, perm) }()
+	go func() { defer wg.Done(); ApplyPermutation(ids, perm) }()
+	wg.Wait()
+
+	if !sort.IntsAreSorted(ages) {
+		t.Errorf("ages = %v, want sorted", ages)
+	}
+	if expected := []string{"alice", "erin", "carol", "bob", "dave"}; !reflect.DeepEqual(names, expected) {
+		t.Errorf("names = %v, want %v", names, expected)
+	}
+	if expected := []int{2, 5, 1, 4, 3}; !reflect.DeepEqual(ids, expected) {
+		t.Errorf("ids = %v, want %v", ids, expected)
+	}
+}
+
+func BenchmarkArgSort(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(100000)
+
+	b.Run("ArgSort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ArgSort(data, comp)
+		}
+	})
+	b.Run("ParallelArgSort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ParallelArgSort(data, comp)
+		}
+	})
+}
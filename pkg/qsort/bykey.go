@@ -0,0 +1,85 @@
+package qsort
+
+import (
+	"context"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// keyed — ключ элемента и его исходный индекс
+type keyed[K any] struct {
+	key   K
+	index int
+}
+
+// keyedC сравнивает пары по ключу, а при равенстве — по индексу
+type keyedC[K any] struct {
+	kc comparator.Comparator[K]
+}
+
+func (c keyedC[K]) Compare(a, b keyed[K]) int {
+	if r := c.kc.Compare(a.key, b.key); r != 0 {
+		return r
+	}
+	if a.index < b.index {
+		return -1
+	}
+	if a.index > b.index {
+		return 1
+	}
+	return 0
+}
+
+// SortByKey сортирует data по ключам key(x), сравниваемым kc (decorate-sort-undecorate).
+// В отличие от Sort с comparator.By, key вызывается ровно один раз для каждого элемента:
+// ключи вычисляются параллельно, пары (ключ, индекс) сортируются Sort с параметрами opts,
+// после чего data переставляется на месте. Элементы с равными ключами сохраняют
+// исходный порядок. Подходит для дорогих ключей — разбора дат, хешей и т. п.
+// ценой памяти под n пар
+func SortByKey[T, K any](data []T, key func(T) K, kc comparator.Comparator[K], opts ...Option) {
+	if len(data) <= 1 {
+		return
+	}
+	c := newConfig(opts)
+
+	pairs := make([]keyed[K], len(data))
+	parallelFor(chunks(len(data), c.maxGoroutines, c.threshold), func(_ int, s span) {
+		for i := s.start; i < s.end; i++ {
+			pairs[i] = keyed[K]{key(data[i]), i}
+		}
+	})
+
+	sortWith(context.Background(), pairs, keyedC[K]{kc}, c)
+
+	perm := make([]int, len(pairs))
+	for i, p := range pairs {
+		perm[i] = p.index
+	}
+	ApplyPermutation(data, perm)
+}
+
+// chunks делит [0, n) на не более чем workers отрезков не короче threshold
+func chunks(n, workers, threshold int) []span {
+	workers = max(1, min(workers, n/max(threshold, 1)))
+	size := (n + workers - 1) / workers
+
+	parts := make([]span, 0, workers)
+	for lo := 0; lo < n; lo += size {
+		parts = append(parts, span{lo, min(lo+size, n)})
+	}
+	return parts
+}
+
+// parallelFor вызывает body для каждого отрезка parts, каждый раз в своей горутине
+func parallelFor(parts []span, body func(i int, s span)) {
+	if len(parts) == 1 {
+		body(0, parts[0])
+		return
+	}
+
+	var g panicGroup
+	for i, s := range parts {
+		g.start(func() { body(i, s) })
+	}
+	g.wait()
+}
@@ -0,0 +1,152 @@
+package qsort
+
+import (
+	"fmt"
+	"reflect"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+func recordKey(r record) int { return r.key }
+
+func TestSortByKey(t *testing.T) {
+	tests := []struct {
+		name string
+		size int
+		opts []Option
+	}{
+		{"Empty", 0, nil},
+		{"Single element", 1, nil},
+		{"Small", 100, nil},
+		{"Parallel", 50000, nil},
+		{"Sequential", 50000, []Option{WithMaxGoroutines(1)}},
+		{"Pdq", 50000, []Option{WithPartition(PartitionPdq)}},
+		{"Stable", 50000, []Option{WithStable()}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := generateRecords(tt.size, 100)
+			expected := stableSorted(data)
+
+			var calls atomic.Int64
+			SortByKey(data, func(r record) int {
+				calls.Add(1)
+				return r.key
+			}, comparator.OrderedC[int]{}, tt.opts...)
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Error("SortByKey() result differs from stable sort by key")
+			}
+			if n := calls.Load(); tt.size > 1 && n != int64(tt.size) {
+				t.Errorf("key was called %d times, want %d", n, tt.size)
+			}
+		})
+	}
+}
+
+func TestSortByKeyCollation(t *testing.T) {
+	// Ключи CollateC.Key упорядочены так же, как строки по CollateC.Compare
+	words := []string{"Zebra", "ärger", "apple", "Ärger", "Apple", "zoo", "Äpfel", "arg", "résumé", "resume"}
+	var data []string
+	for i := 0; i < 5000; i++ {
+		data = append(data, words[(i*7)%len(words)])
+	}
+	c := comparator.CollateC{}
+	expected := copySlice(data)
+	Sort(expected, c)
+
+	SortByKey(data, c.Key, comparator.BytesC{})
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Error("SortByKey(CollateC.Key) result differs from Sort(CollateC)")
+	}
+}
+
+func TestSortByKeyPanic(t *testing.T) {
+	data := generateRecords(20000, 100)
+	original := copySlice(data)
+
+	e := recoverPanicError(t, func() {
+		SortByKey(data, func(r record) int {
+			if r.seq == 15000 {
+				panic("bad key")
+			}
+			return r.key
+		}, comparator.OrderedC[int]{}, WithMaxGoroutines(4))
+	})
+	if e.Value != "bad key" {
+		t.Errorf("PanicError.Value = %v, want %q", e.Value, "bad key")
+	}
+	// Паника при вычислении ключей не трогает data
+	if !reflect.DeepEqual(data, original) {
+		t.Error("data changed after panic in key")
+	}
+}
+
+func TestParallelFor(t *testing.T) {
+	for _, n := range []int{0, 1, 999, 1000, 10007} {
+		t.Run(fmt.Sprint("n_", n), func(t *testing.T) {
+			visited := make([]int32, n)
+			parts := chunks(n, 4, 1000)
+			if len(parts) > 4 || (n >= 4000 && len(parts) != 4) {
+				t.Errorf("chunks(%d, 4, 1000) = %v", n, parts)
+			}
+			parallelFor(parts, func(_ int, s span) {
+				for i := s.start; i < s.end; i++ {
+					atomic.AddInt32(&visited[i], 1)
+				}
+			})
+			for i, v := range visited {
+				if v != 1 {
+					t.Fatalf("Index %d visited %d times", i, v)
+				}
+			}
+		})
+	}
+}
+
+// Ключи сортировки — метки времени в виде строк: их разбор заметно дороже сравнения
+func generateTimestamps(size int) []string {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	data := make([]string, size)
+	for i, v := range GenerateRandomInts(size) {
+		data[i] = base.Add(time.Duration(v) * time.Second).Format(time.RFC3339)
+	}
+	return data
+}
+
+func BenchmarkSortByKey(b *testing.B) {
+	data := generateTimestamps(100000)
+	var calls atomic.Int64
+	parse := func(s string) int64 {
+		calls.Add(1)
+		t, err := time.Parse(time.RFC3339, s)
+		if err != nil {
+			panic(err)
+		}
+		return t.Unix()
+	}
+	keyC := comparator.OrderedC[int64]{}
+
+	b.Run("Sort_By", func(b *testing.B) {
+		calls.Store(0)
+		for i := 0; i < b.N; i++ {
+			testData := copySlice(data)
+			Sort(testData, comparator.By(parse, keyC))
+		}
+		b.ReportMetric(float64(calls.Load())/float64(b.N), "keys/op")
+	})
+
+	b.Run("SortByKey", func(b *testing.B) {
+		calls.Store(0)
+		for i := 0; i < b.N; i++ {
+			testData := copySlice(data)
+			SortByKey(testData, parse, keyC)
+		}
+		b.ReportMetric(float64(calls.Load())/float64(b.N), "keys/op")
+	})
+}
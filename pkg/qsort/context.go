@@ -0,0 +1,53 @@
+package qsort
+
+import (
+	"context"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// ParallelQuickSortContext — ParallelQuickSort с возможностью отмены через ctx.
+// Каждая горутина проверяет ctx перед очередным разбиением и после отмены
+// не порождает новых задач. Если сортировка прервана, возвращается ctx.Err(),
+// а data остаётся перестановкой исходных элементов, но не обязательно отсортирована.
+// Если ctx уже отменён, data не изменяется.
+// Паника в comp не распространяется, а возвращается как *PanicError;
+// data при этом так же остаётся перестановкой исходных элементов
+func ParallelQuickSortContext[T any](ctx context.Context, data []T, comp comparator.Comparator[T]) (err error) {
+	defer recoverPanic(&err)
+	return sortWith(ctx, data, comp, newConfig(nil))
+}
+
+// SortContext — Sort с возможностью отмены через ctx. Состояние data после отмены
+// и обработка паник такие же, как в ParallelQuickSortContext, кроме устойчивой
+// сортировки (WithStable). Она отмену не поддерживает и всегда выполняется до конца,
+// а после паники в comp data остаётся перестановкой исходных элементов, только
+// если задан WithScratchBuffer(ScratchNone): прерванное слияние через буфер
+// может оставить в data копии одних элементов вместо других
+func SortContext[T any](ctx context.Context, data []T, comp comparator.Comparator[T], opts ...Option) (err error) {
+	defer recoverPanic(&err)
+	return sortWith(ctx, data, comp, newConfig(opts))
+}
+
+// SequentialQuickSortContext — SequentialQuickSort с возможностью отмены через ctx.
+// Состояние data после отмены и обработка паник такие же, как в ParallelQuickSortContext
+func SequentialQuickSortContext[T any](ctx context.Context, data []T, comp comparator.Comparator[T]) (err error) {
+	defer recoverPanic(&err)
+	if err := ctx.Err(); err != nil || len(data) <= 1 {
+		return err
+	}
+
+	introSort(data, comp, maxDepth(len(data)), insertionSortThreshold, lomutoPartition[T], ctx.Done())
+	return ctx.Err()
+}
+
+// canceled сообщает, закрыт ли канал отмены cancel, не блокируясь.
+// Nil-канал означает, что сортировку отменить нельзя
+func canceled(cancel <-chan struct{}) bool {
+	select {
+	case <-cancel:
+		return true
+	default:
+		return false
+	}
+}
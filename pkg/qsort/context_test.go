@@ -0,0 +1,135 @@
+package qsort
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// cancelingComparator отменяет контекст после заданного числа сравнений
+type cancelingComparator struct {
+	cancel      context.CancelFunc
+	after       int64
+	comparisons atomic.Int64
+}
+
+func (c *cancelingComparator) Compare(a, b int) int {
+	if c.comparisons.Add(1) == c.after {
+		c.cancel()
+	}
+	return comparator.OrderedC[int]{}.Compare(a, b)
+}
+
+// isPermutation проверяет, что data состоит из тех же элементов, что и original
+func isPermutation(data, original []int) bool {
+	got, want := copySlice(data), copySlice(original)
+	sort.Ints(got)
+	sort.Ints(want)
+	return reflect.DeepEqual(got, want)
+}
+
+func TestQuickSortContext(t *testing.T) {
+	sorts := []struct {
+		name string
+		sort func(ctx context.Context, data []int, comp *cancelingComparator) error
+	}{
+		{"Sequential", func(ctx context.Context, data []int, comp *cancelingComparator) error {
+			return SequentialQuickSortContext(ctx, data, comp)
+		}},
+		{"Parallel", func(ctx context.Context, data []int, comp *cancelingComparator) error {
+			return ParallelQuickSortContext(ctx, data, comp)
+		}},
+		{"SortContext_ThreeWay", func(ctx context.Context, data []int, comp *cancelingComparator) error {
+			return SortContext(ctx, data, comp, WithPartition(PartitionThreeWay), WithMaxGoroutines(4))
+		}},
+		{"Parallel_goroutines_8", func(ctx context.Context, data []int, comp *cancelingComparator) error {
+			return parallelQuickSort(ctx, data, comp, 8, parallelThreshold, lomutoScheme(medianOfThree[int], insertionSortThreshold))
+		}},
+	}
+
+	const n = 200000
+	original := GenerateRandomInts(n)
+
+	for _, s := range sorts {
+		t.Run(s.name+"_Completed", func(t *testing.T) {
+			data := copySlice(original)
+			comp := &cancelingComparator{cancel: func() {}, after: -1}
+
+			if err := s.sort(context.Background(), data, comp); err != nil {
+				t.Fatalf("Sort returned %v, want nil", err)
+			}
+			if !isSorted(data, comparator.OrderedC[int]{}) {
+				t.Error("Data is not sorted")
+			}
+		})
+
+		t.Run(s.name+"_AlreadyCanceled", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			cancel()
+			data := copySlice(original)
+			comp := &cancelingComparator{cancel: cancel, after: -1}
+
+			if err := s.sort(ctx, data, comp); !errors.Is(err, context.Canceled) {
+				t.Fatalf("Sort returned %v, want %v", err, context.Canceled)
+			}
+			if !reflect.DeepEqual(data, original) {
+				t.Error("Data was modified although the context was already canceled")
+			}
+			if got := comp.comparisons.Load(); got != 0 {
+				t.Errorf("Sort made %d comparisons, want 0", got)
+			}
+		})
+
+		t.Run(s.name+"_CanceledDuringSort", func(t *testing.T) {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			data := copySlice(original)
+			comp := &cancelingComparator{cancel: cancel, after: 2 * n}
+
+			if err := s.sort(ctx, data, comp); !errors.Is(err, context.Canceled) {
+				t.Fatalf("Sort returned %v, want %v", err, context.Canceled)
+			}
+			if !isPermutation(data, original) {
+				t.Error("Canceled sort lost or duplicated elements")
+			}
+
+			// После отмены дорабатывают только начатые разбиения, а они
+			// не пересекаются и в сумме не длиннее массива
+			if got, limit := comp.comparisons.Load(), int64(4*n); got > limit {
+				t.Errorf("Sort made %d comparisons after cancellation at %d, want <= %d", got, comp.after, limit)
+			}
+		})
+	}
+}
+
+func TestQuickSortContextEmpty(t *testing.T) {
+	if err := ParallelQuickSortContext(context.Background(), []int{}, comparator.OrderedC[int]{}); err != nil {
+		t.Errorf("ParallelQuickSortContext() on empty slice returned %v", err)
+	}
+	if err := SequentialQuickSortContext(context.Background(), []int{42}, comparator.OrderedC[int]{}); err != nil {
+		t.Errorf("SequentialQuickSortContext() on single element returned %v", err)
+	}
+}
+
+func TestRunPoolCanceled(t *testing.T) {
+	// После отмены задачи, оставшиеся в очереди, не выполняются.
+	// Воркер один, поэтому до отмены порождённые задачи никто не заберёт
+	ctx, cancel := context.WithCancel(context.Background())
+	var executed atomic.Int64
+
+	runPool(ctx, 1, func(w *worker) {
+		for i := 0; i < 100; i++ {
+			w.spawn(func(*worker) { executed.Add(1) })
+		}
+		cancel()
+	})
+
+	if got := executed.Load(); got != 0 {
+		t.Errorf("runPool executed %d tasks after cancellation, want 0", got)
+	}
+}
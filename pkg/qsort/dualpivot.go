@@ -0,0 +1,148 @@
+package qsort
+
+import (
+	"context"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// DualPivotQuickSort — последовательная быстрая сортировка с двумя опорными элементами
+// (схема Ярославского). Глубина рекурсии ограничена так же, как в SequentialQuickSort
+func DualPivotQuickSort[T any](data []T, comp comparator.Comparator[T]) {
+	if len(data) <= 1 {
+		return
+	}
+
+	dualPivotSort(data, comp, maxDepth(len(data)), insertionSortThreshold, nil)
+}
+
+// ParallelDualPivotQuickSort — параллельная быстрая сортировка с двумя опорными элементами.
+// Каждое разбиение даёт три части: две из них уходят в очередь пула воркеров,
+// с третьей, самой большой, воркер продолжает сам
+func ParallelDualPivotQuickSort[T any](data []T, comp comparator.Comparator[T]) {
+	Sort(data, comp, WithPartition(PartitionDualPivot))
+}
+
+// parallelDualPivotSort сортирует data в пуле из maxGoroutines воркеров.
+// Подмассивы короче threshold сортируются последовательно, не длиннее cutoff — вставками.
+// После отмены ctx новые разбиения не начинаются, и возвращается ctx.Err()
+func parallelDualPivotSort[T any](ctx context.Context, data []T, comp comparator.Comparator[T], maxGoroutines, threshold, cutoff int) error {
+	if err := ctx.Err(); err != nil || len(data) <= 1 {
+		return err
+	}
+
+	// Для небольших массивов используем последовательную сортировку
+	if len(data) < threshold || maxGoroutines <= 1 {
+		dualPivotSort(data, comp, maxDepth(len(data)), cutoff, ctx.Done())
+		return ctx.Err()
+	}
+
+	runPool(ctx, maxGoroutines, dualPivotTask(data, comp, maxDepth(len(data)), threshold, cutoff))
+	return ctx.Err()
+}
+
+// dualPivotTask возвращает задачу сортировки data для пула воркеров
+func dualPivotTask[T any](data []T, comp comparator.Comparator[T], depthLimit, threshold, cutoff int) task {
+	return func(w *worker) {
+		// Разбиение требует хотя бы двух элементов
+		for len(data) > 1 && len(data) >= threshold && depthLimit > 0 {
+			if w.canceled() {
+				return
+			}
+			depthLimit--
+
+			lt, gt := dualPivotPartition(data, comp)
+
+			parts := [3][]T{data[:lt], data[lt+1 : gt], data[gt+1:]}
+			if comp.Compare(data[lt], data[gt]) == 0 {
+				// Опорные элементы равны, значит средняя часть состоит из равных им элементов
+				parts[1] = nil
+			}
+
+			largest := 0
+			for i := range parts {
+				if len(parts[i]) > len(parts[largest]) {
+					largest = i
+				}
+			}
+			for i, part := range parts {
+				if i != largest && len(part) > 1 {
+					w.spawn(dualPivotTask(part, comp, depthLimit, threshold, cutoff))
+				}
+			}
+			data = parts[largest]
+		}
+
+		dualPivotSort(data, comp, depthLimit, cutoff, w.pool.cancel)
+	}
+}
+
+// dualPivotSort сортирует data, досортировывая вставками подмассивы не длиннее cutoff.
+// Перед каждым разбиением проверяется канал отмены cancel
+func dualPivotSort[T any](data []T, comp comparator.Comparator[T], depthLimit, cutoff int, cancel <-chan struct{}) {
+	for len(data) > cutoff {
+		if canceled(cancel) {
+			return
+		}
+		if depthLimit == 0 {
+			heapSort(data, comp)
+			return
+		}
+		depthLimit--
+
+		lt, gt := dualPivotPartition(data, comp)
+
+		// Среднюю часть сортируем, только если опорные элементы различны
+		dualPivotSort(data[:lt], comp, depthLimit, cutoff, cancel)
+		if comp.Compare(data[lt], data[gt]) != 0 {
+			dualPivotSort(data[lt+1:gt], comp, depthLimit, cutoff, cancel)
+		}
+		data = data[gt+1:]
+	}
+
+	insertionSort(data, comp)
+}
+
+// dualPivotPartition разбивает массив относительно двух опорных элементов p <= q:
+// data[:lt] < p, p <= data[lt+1:gt] <= q, data[gt+1:] > q.
+// Возвращает позиции опорных элементов lt и gt. Массив должен содержать
+// хотя бы два элемента
+func dualPivotPartition[T any](data []T, comp comparator.Comparator[T]) (lt, gt int) {
+	lo, hi := 0, len(data)-1
+
+	// В качестве опорных берём элементы на границах третей
+	third := len(data) / 3
+	data[lo], data[lo+third] = data[lo+third], data[lo]
+	data[hi], data[hi-third] = data[hi-third], data[hi]
+	if comp.Compare(data[lo], data[hi]) > 0 {
+		data[lo], data[hi] = data[hi], data[lo]
+	}
+	p, q := data[lo], data[hi]
+
+	// Инвариант: data[lo+1:l] < p, p <= data[l:k] <= q, data[g+1:hi] > q
+	l, g := lo+1, hi-1
+	for k := l; k <= g; k++ {
+		if comp.Compare(data[k], p) < 0 {
+			data[k], data[l] = data[l], data[k]
+			l++
+		} else if comp.Compare(data[k], q) > 0 {
+			for comp.Compare(data[g], q) > 0 && k < g {
+				g--
+			}
+			data[k], data[g] = data[g], data[k]
+			g--
+			if comp.Compare(data[k], p) < 0 {
+				data[k], data[l] = data[l], data[k]
+				l++
+			}
+		}
+	}
+	l--
+	g++
+
+	// Ставим опорные элементы на их места
+	data[lo], data[l] = data[l], data[lo]
+	data[hi], data[g] = data[g], data[hi]
+
+	return l, g
+}
@@ -0,0 +1,240 @@
+package qsort
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+	"github.com/leonid-voroshilov/mm-qsort/pkg/generator"
+)
+
+// Тесты для DualPivotQuickSort
+func TestDualPivotQuickSort(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	tests := []struct {
+		name string
+		data []int
+	}{
+		{"Empty slice", []int{}},
+		{"Single element", []int{42}},
+		{"Two elements sorted", []int{1, 2}},
+		{"Two elements reversed", []int{2, 1}},
+		{"Small sorted", []int{1, 2, 3, 4, 5}},
+		{"Small reversed", []int{5, 4, 3, 2, 1}},
+		{"Small random", []int{3, 1, 4, 1, 5, 9, 2, 6, 5}},
+		{"Duplicates", []int{1, 1, 1, 1, 1}},
+		{"Mixed duplicates", []int{3, 1, 4, 1, 5, 3, 2, 4, 5}},
+		{"Few unique", generator.New(1).Ints(generator.FewUnique(3), 5000)},
+		{"Organ pipe", generator.New(1).Ints(generator.OrganPipe(), 5000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+			expected := copySlice(tt.data)
+			sort.Ints(expected)
+
+			DualPivotQuickSort(data, comp)
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Errorf("DualPivotQuickSort() = %v, want %v", data, expected)
+			}
+
+			if !isSorted(data, comp) {
+				t.Errorf("Result is not sorted: %v", data)
+			}
+		})
+	}
+}
+
+// Тесты для ParallelDualPivotQuickSort
+func TestParallelDualPivotQuickSort(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	tests := []struct {
+		name string
+		data []int
+	}{
+		{"Empty slice", []int{}},
+		{"Single element", []int{42}},
+		{"Small random", []int{3, 1, 4, 1, 5, 9, 2, 6, 5}},
+		{"Duplicates", []int{1, 1, 1, 1, 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+			expected := copySlice(tt.data)
+			sort.Ints(expected)
+
+			ParallelDualPivotQuickSort(data, comp)
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Errorf("ParallelDualPivotQuickSort() = %v, want %v", data, expected)
+			}
+		})
+	}
+}
+
+func TestParallelDualPivotQuickSortLargeData(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+	sizes := []int{1000, 5000, 10000, 100000}
+
+	for _, size := range sizes {
+		t.Run("size_"+strconv.Itoa(size), func(t *testing.T) {
+			data := GenerateRandomInts(size)
+			expected := copySlice(data)
+			sort.Ints(expected)
+
+			// Явно задаём бюджет горутин, чтобы проверить параллельную рекурсию на любой машине
+			parallelDualPivotSort(context.Background(), data, comp, 8, parallelThreshold, insertionSortThreshold)
+
+			if !isSorted(data, comp) {
+				t.Errorf("Large data set (size %d) is not sorted", size)
+			}
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Errorf("Large data set (size %d) doesn't match expected result", size)
+			}
+		})
+	}
+}
+
+// Тесты для dualPivotPartition
+func TestDualPivotPartition(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	tests := []struct {
+		name string
+		data []int
+	}{
+		{"Two elements", []int{2, 1}},
+		{"All equal", []int{7, 7, 7, 7, 7}},
+		{"Multiple elements", []int{3, 1, 4, 1, 5, 9, 2, 6, 5, 3, 5}},
+		{"Random", GenerateRandomInts(1000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+
+			lt, gt := dualPivotPartition(data, comp)
+			p, q := data[lt], data[gt]
+
+			if lt >= gt {
+				t.Fatalf("dualPivotPartition() returned lt = %d >= gt = %d", lt, gt)
+			}
+			for i := 0; i < lt; i++ {
+				if comp.Compare(data[i], p) >= 0 {
+					t.Errorf("Element %v at index %d should be less than pivot %v", data[i], i, p)
+				}
+			}
+			for i := lt + 1; i < gt; i++ {
+				if comp.Compare(data[i], p) < 0 || comp.Compare(data[i], q) > 0 {
+					t.Errorf("Element %v at index %d should be between pivots %v and %v", data[i], i, p, q)
+				}
+			}
+			for i := gt + 1; i < len(data); i++ {
+				if comp.Compare(data[i], q) <= 0 {
+					t.Errorf("Element %v at index %d should be greater than pivot %v", data[i], i, q)
+				}
+			}
+		})
+	}
+}
+
+// Тесты со строками
+func TestParallelDualPivotQuickSortStrings(t *testing.T) {
+	comp := comparator.OrderedC[string]{}
+	data := []string{"zebra", "apple", "banana", "cherry", "date"}
+	expected := []string{"apple", "banana", "cherry", "date", "zebra"}
+
+	ParallelDualPivotQuickSort(data, comp)
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("ParallelDualPivotQuickSort(strings) = %v, want %v", data, expected)
+	}
+}
+
+// Тесты на граничные случаи
+func TestDualPivotEdgeCases(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	t.Run("All same elements", func(t *testing.T) {
+		data := make([]int, 10000)
+		for i := range data {
+			data[i] = 42
+		}
+
+		parallelDualPivotSort(context.Background(), data, comp, 8, parallelThreshold, insertionSortThreshold)
+
+		for _, v := range data {
+			if v != 42 {
+				t.Error("Elements changed in all-same slice")
+				break
+			}
+		}
+	})
+
+	t.Run("Adversary", func(t *testing.T) {
+		const n = 4096
+
+		adversary := newAntiQuicksort(n)
+		DualPivotQuickSort(generateSortedInts(n), adversary)
+
+		if limit := nLogN(n, 8); adversary.comparisons > limit {
+			t.Errorf("DualPivotQuickSort made %d comparisons on adversarial input, want <= %d", adversary.comparisons, limit)
+		}
+	})
+}
+
+// Тест на корректность работы с горутинами
+func TestDualPivotConcurrencySafety(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	const numGoroutines = 10
+	const dataSize = 5000
+
+	results := make(chan bool, numGoroutines)
+
+	for i := 0; i < numGoroutines; i++ {
+		go func() {
+			data := GenerateRandomInts(dataSize)
+			parallelDualPivotSort(context.Background(), data, comp, 4, parallelThreshold, insertionSortThreshold)
+			results <- isSorted(data, comp)
+		}()
+	}
+
+	for i := 0; i < numGoroutines; i++ {
+		if !<-results {
+			t.Error("Concurrent sorting failed")
+		}
+	}
+}
+
+func BenchmarkDualPivotVsSinglePivot(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(100000)
+
+	b.Run("SinglePivot", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			testData := copySlice(data)
+			b.StartTimer()
+			ParallelQuickSort(testData, comp)
+			b.StopTimer()
+		}
+	})
+
+	b.Run("DualPivot", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			testData := copySlice(data)
+			b.StartTimer()
+			ParallelDualPivotQuickSort(testData, comp)
+			b.StopTimer()
+		}
+	})
+}
@@ -0,0 +1,106 @@
+package qsort
+
+import (
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// insertionSortThreshold — подмассивы не длиннее этого размера досортировываются вставками
+const insertionSortThreshold = 12
+
+// maxDepth возвращает допустимую глубину рекурсии быстрой сортировки: 2·⌈log2(n+1)⌉.
+// После её превышения introSort переключается на пирамидальную сортировку
+func maxDepth(n int) int {
+	depth := 0
+	for i := n; i > 0; i >>= 1 {
+		depth++
+	}
+	return depth * 2
+}
+
+// introSort — быстрая сортировка с ограничением глубины рекурсии.
+// Подмассивы не длиннее cutoff досортировываются вставками.
+// Если разбиения раз за разом получаются неудачными (например, на входе,
+// подобранном против медианы из трёх), оставшийся подмассив сортируется
+// пирамидально, что гарантирует O(n log n) в худшем случае.
+// Перед каждым разбиением проверяется канал отмены cancel: после его закрытия
+// сортировка прекращается, оставляя data перестановкой исходных элементов
+func introSort[T any](data []T, comp comparator.Comparator[T], depthLimit, cutoff int, part partitionFunc[T], cancel <-chan struct{}) {
+	for len(data) > cutoff {
+		if canceled(cancel) {
+			return
+		}
+		if depthLimit == 0 {
+			heapSort(data, comp)
+			return
+		}
+		depthLimit--
+
+		lo, hi := part(data, comp)
+
+		// Рекурсивно сортируем меньшую часть, а большую — в цикле,
+		// чтобы глубина стека оставалась логарифмической
+		left, right := data[:lo], data[hi:]
+		if len(left) < len(right) {
+			introSort(left, comp, depthLimit, cutoff, part, cancel)
+			data = right
+		} else {
+			introSort(right, comp, depthLimit, cutoff, part, cancel)
+			data = left
+		}
+	}
+
+	insertionSort(data, comp)
+}
+
+// partitionFunc разбивает data относительно опорного элемента и возвращает
+// границы [lo, hi) отрезка элементов, равных опорному и стоящих на своих местах
+type partitionFunc[T any] func(data []T, comp comparator.Comparator[T]) (lo, hi int)
+
+// lomutoPartition приводит partition к виду partitionFunc
+func lomutoPartition[T any](data []T, comp comparator.Comparator[T]) (int, int) {
+	pivotIndex := partition(data, comp)
+	return pivotIndex, pivotIndex + 1
+}
+
+// insertionSort — сортировка вставками для коротких подмассивов
+func insertionSort[T any](data []T, comp comparator.Comparator[T]) {
+	for i := 1; i < len(data); i++ {
+		for j := i; j > 0 && comp.Compare(data[j-1], data[j]) > 0; j-- {
+			data[j-1], data[j] = data[j], data[j-1]
+		}
+	}
+}
+
+// heapSort — пирамидальная сортировка, используется как запасной вариант в introSort
+func heapSort[T any](data []T, comp comparator.Comparator[T]) {
+	length := len(data)
+
+	// Строим max-кучу
+	for i := length/2 - 1; i >= 0; i-- {
+		siftDown(data, comp, i, length)
+	}
+
+	// Переносим максимум в конец и восстанавливаем кучу на оставшейся части
+	for end := length - 1; end > 0; end-- {
+		data[0], data[end] = data[end], data[0]
+		siftDown(data, comp, 0, end)
+	}
+}
+
+// siftDown просеивает элемент root вниз в max-куче data[:end]
+func siftDown[T any](data []T, comp comparator.Comparator[T], root, end int) {
+	for {
+		child := 2*root + 1
+		if child >= end {
+			return
+		}
+		if child+1 < end && comp.Compare(data[child], data[child+1]) < 0 {
+			child++
+		}
+		if comp.Compare(data[root], data[child]) >= 0 {
+			return
+		}
+		data[root], data[child] = data[child], data[root]
+		root = child
+	}
+}
@@ -0,0 +1,212 @@
+package qsort
+
+import (
+	"math/bits"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// antiQuicksort — противник Макилроя ("A Killer Adversary for Quicksort").
+// Значения элементов назначаются лениво во время сравнений так, чтобы
+// опорный элемент каждый раз оказывался почти минимальным
+type antiQuicksort struct {
+	val         []int
+	gas         int
+	nsolid      int
+	candidate   int
+	comparisons int
+}
+
+func newAntiQuicksort(n int) *antiQuicksort {
+	a := &antiQuicksort{val: make([]int, n), gas: n}
+	for i := range a.val {
+		a.val[i] = a.gas
+	}
+	return a
+}
+
+func (a *antiQuicksort) freeze(x int) {
+	a.val[x] = a.nsolid
+	a.nsolid++
+}
+
+func (a *antiQuicksort) Compare(x, y int) int {
+	a.comparisons++
+	if a.val[x] == a.gas && a.val[y] == a.gas {
+		if x == a.candidate {
+			a.freeze(x)
+		} else {
+			a.freeze(y)
+		}
+	}
+	if a.val[x] == a.gas {
+		a.candidate = x
+	} else if a.val[y] == a.gas {
+		a.candidate = y
+	}
+	return comparator.OrderedC[int]{}.Compare(a.val[x], a.val[y])
+}
+
+// countingComparator считает количество вызовов Compare, в том числе из разных горутин
+type countingComparator struct {
+	comparisons atomic.Int64
+}
+
+func (c *countingComparator) Compare(a, b int) int {
+	c.comparisons.Add(1)
+	return comparator.OrderedC[int]{}.Compare(a, b)
+}
+
+func (c *countingComparator) count() int {
+	return int(c.comparisons.Load())
+}
+
+// nLogN — верхняя оценка числа сравнений c·n·log2(n)
+func nLogN(n, c int) int {
+	return c * n * bits.Len(uint(n))
+}
+
+func TestSequentialQuickSortAdversary(t *testing.T) {
+	const n = 4096
+
+	adversary := newAntiQuicksort(n)
+	indices := generateSortedInts(n)
+
+	SequentialQuickSort(indices, adversary)
+
+	if limit := nLogN(n, 8); adversary.comparisons > limit {
+		t.Errorf("SequentialQuickSort made %d comparisons on adversarial input, want <= %d", adversary.comparisons, limit)
+	}
+
+	// Повторяем сортировку на входе, который сгенерировал противник
+	data := copySlice(adversary.val)
+	expected := copySlice(data)
+	sort.Ints(expected)
+
+	counter := &countingComparator{}
+	SequentialQuickSort(data, counter)
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Error("Adversarial input is not sorted correctly")
+	}
+	if limit := nLogN(n, 8); counter.count() > limit {
+		t.Errorf("SequentialQuickSort made %d comparisons on replayed adversarial input, want <= %d", counter.count(), limit)
+	}
+}
+
+func TestParallelQuickSortAdversary(t *testing.T) {
+	const n = 100000
+
+	// Все элементы равны: разбиение Ломуто каждый раз отщепляет один элемент
+	data := make([]int, n)
+	counter := &countingComparator{}
+
+	ParallelQuickSortWithThreshold(data, counter, n+1)
+
+	if limit := nLogN(n, 8); counter.count() > limit {
+		t.Errorf("ParallelQuickSortWithThreshold made %d comparisons on equal elements, want <= %d", counter.count(), limit)
+	}
+}
+
+func TestHeapSort(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	tests := []struct {
+		name string
+		data []int
+	}{
+		{"Empty slice", []int{}},
+		{"Single element", []int{42}},
+		{"Two elements reversed", []int{2, 1}},
+		{"Small random", []int{3, 1, 4, 1, 5, 9, 2, 6, 5}},
+		{"Reverse sorted", generateReversedInts(100)},
+		{"Random", GenerateRandomInts(1000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+			expected := copySlice(tt.data)
+			sort.Ints(expected)
+
+			heapSort(data, comp)
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Errorf("heapSort() = %v, want %v", data, expected)
+			}
+		})
+	}
+}
+
+func TestInsertionSort(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	tests := []struct {
+		name string
+		data []int
+	}{
+		{"Empty slice", []int{}},
+		{"Single element", []int{42}},
+		{"Small random", []int{3, 1, 4, 1, 5, 9, 2, 6, 5}},
+		{"Reverse sorted", generateReversedInts(insertionSortThreshold)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+			expected := copySlice(tt.data)
+			sort.Ints(expected)
+
+			insertionSort(data, comp)
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Errorf("insertionSort() = %v, want %v", data, expected)
+			}
+		})
+	}
+}
+
+func TestIntroSortDepthLimitZero(t *testing.T) {
+	// С нулевым лимитом introSort сразу переходит на пирамидальную сортировку
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(500)
+	expected := copySlice(data)
+	sort.Ints(expected)
+
+	introSort(data, comp, 0, insertionSortThreshold, lomutoPartition[int], nil)
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Error("introSort with zero depth limit doesn't match expected result")
+	}
+}
+
+func TestMaxDepth(t *testing.T) {
+	tests := []struct {
+		n, expected int
+	}{
+		{0, 0},
+		{1, 2},
+		{2, 4},
+		{1000, 20},
+		{1024, 22},
+	}
+
+	for _, tt := range tests {
+		if result := maxDepth(tt.n); result != tt.expected {
+			t.Errorf("maxDepth(%d) = %d, want %d", tt.n, result, tt.expected)
+		}
+	}
+}
+
+func BenchmarkSequentialQuickSortAllEqual(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	data := make([]int, 100000)
+
+	for i := 0; i < b.N; i++ {
+		SequentialQuickSort(data, comp)
+	}
+}
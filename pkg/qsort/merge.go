@@ -0,0 +1,173 @@
+package qsort
+
+import (
+	"context"
+	"iter"
+	"runtime"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// MergeSorted сливает упорядоченные по comp срезы inputs в новый упорядоченный срез.
+// Слияние устойчиво: равные элементы идут в порядке входов, а внутри входа —
+// в исходном порядке. Небольшие входы сливаются последовательно деревом проигравших
+// за O(n log k), большие — попарно деревом параллельных двухпутевых слияний
+func MergeSorted[T any](comp comparator.Comparator[T], inputs ...[]T) []T {
+	return mergeSorted(comp, inputs, runtime.NumCPU())
+}
+
+func mergeSorted[T any](comp comparator.Comparator[T], inputs [][]T, workers int) []T {
+	total := 0
+	for _, in := range inputs {
+		total += len(in)
+	}
+	dst := make([]T, total)
+
+	if total < parallelThreshold || workers <= 1 || len(inputs) < 2 {
+		pos := make([]int, len(inputs))
+		lt := newLoserTree(comp, len(inputs), func(i int) (T, bool) {
+			if pos[i] == len(inputs[i]) {
+				var zero T
+				return zero, false
+			}
+			pos[i]++
+			return inputs[i][pos[i]-1], true
+		})
+		for k := range dst {
+			dst[k], _ = lt.pop()
+		}
+		return dst
+	}
+
+	runPool(context.Background(), workers, func(w *worker) {
+		mergeTree(w, inputs, dst, make([]T, total), comp, noop)
+	})
+	return dst
+}
+
+// mergeTree устойчиво сливает inputs в dst, используя buf того же размера,
+// и вызывает done. Левая и правая половины входов сливаются параллельно в buf,
+// а затем — параллельным двухпутевым слиянием в dst
+func mergeTree[T any](w *worker, inputs [][]T, dst, buf []T, comp comparator.Comparator[T], done task) {
+	switch len(inputs) {
+	case 1:
+		copy(dst, inputs[0])
+		done(w)
+		return
+	case 2:
+		parallelMerge(w, inputs[0], inputs[1], dst, comp, done)
+		return
+	}
+
+	h := len(inputs) / 2
+	mid := 0
+	for _, in := range inputs[:h] {
+		mid += len(in)
+	}
+	w.fork(
+		func(w *worker, done task) { mergeTree(w, inputs[:h], buf[:mid], dst[:mid], comp, done) },
+		func(w *worker, done task) { mergeTree(w, inputs[h:], buf[mid:], dst[mid:], comp, done) },
+		func(w *worker) { parallelMerge(w, buf[:mid], buf[mid:], dst, comp, done) },
+	)
+}
+
+// MergeSortedSeq лениво сливает упорядоченные по comp последовательности seqs.
+// Слияние устойчиво, как в MergeSorted. Каждая последовательность читается
+// через iter.Pull не дальше, чем нужно для очередного элемента, так что
+// подходит и для бесконечных последовательностей
+func MergeSortedSeq[T any](comp comparator.Comparator[T], seqs ...iter.Seq[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		nexts := make([]func() (T, bool), len(seqs))
+		for i, seq := range seqs {
+			next, stop := iter.Pull(seq)
+			defer stop()
+			nexts[i] = next
+		}
+
+		lt := newLoserTree(comp, len(seqs), func(i int) (T, bool) { return nexts[i]() })
+		for {
+			v, ok := lt.pop()
+			if !ok || !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// loserTree — дерево проигравших для k-путевого слияния. Листья k..2k-1 —
+// текущие головы входов, во внутренних узлах 1..k-1 хранятся проигравшие
+// в сравнении на этом узле, в nodes[0] — общий победитель. После извлечения
+// победителя переигрываются только узлы на пути от его листа к корню:
+// log k сравнений, вдвое меньше, чем при просеивании в куче
+type loserTree[T any] struct {
+	comp  comparator.Comparator[T]
+	next  func(i int) (T, bool)
+	heads []T
+	live  []bool
+	nodes []int
+}
+
+// newLoserTree строит дерево над k входами, очередной элемент входа i возвращает next(i)
+func newLoserTree[T any](comp comparator.Comparator[T], k int, next func(i int) (T, bool)) *loserTree[T] {
+	lt := &loserTree[T]{
+		comp:  comp,
+		next:  next,
+		heads: make([]T, k),
+		live:  make([]bool, k),
+		nodes: make([]int, max(k, 1)),
+	}
+	for i := range k {
+		lt.heads[i], lt.live[i] = next(i)
+	}
+	if k > 0 {
+		lt.nodes[0] = lt.build(1)
+	}
+	return lt
+}
+
+// build разыгрывает поддерево node и возвращает его победителя
+func (lt *loserTree[T]) build(node int) int {
+	k := len(lt.heads)
+	if node >= k {
+		return node - k
+	}
+	a, b := lt.build(2*node), lt.build(2*node+1)
+	if lt.beats(b, a) {
+		a, b = b, a
+	}
+	lt.nodes[node] = b
+	return a
+}
+
+// beats сообщает, идёт ли голова входа a раньше головы входа b.
+// Исчерпанный вход проигрывает всем, при равенстве побеждает вход с меньшим номером
+func (lt *loserTree[T]) beats(a, b int) bool {
+	if !lt.live[a] || !lt.live[b] {
+		return lt.live[a]
+	}
+	if r := lt.comp.Compare(lt.heads[a], lt.heads[b]); r != 0 {
+		return r < 0
+	}
+	return a < b
+}
+
+// pop возвращает наименьшую из голов и продвигает её вход
+func (lt *loserTree[T]) pop() (T, bool) {
+	k := len(lt.heads)
+	if k == 0 || !lt.live[lt.nodes[0]] {
+		var zero T
+		return zero, false
+	}
+
+	win := lt.nodes[0]
+	v := lt.heads[win]
+	lt.heads[win], lt.live[win] = lt.next(win)
+
+	for node := (win + k) / 2; node >= 1; node /= 2 {
+		if lt.beats(lt.nodes[node], win) {
+			lt.nodes[node], win = win, lt.nodes[node]
+		}
+	}
+	lt.nodes[0] = win
+	return v, true
+}
@@ -0,0 +1,191 @@
+package qsort
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"slices"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// sortedShards делит записи на входы заданных размеров и устойчиво сортирует каждый.
+// Номера seq растут от входа к входу, так что ожидаемый результат слияния —
+// устойчивая сортировка всех записей
+func sortedShards(sizes []int, keys int) ([][]record, []record) {
+	total := 0
+	for _, s := range sizes {
+		total += s
+	}
+	all := generateRecords(total, keys)
+
+	shards := make([][]record, len(sizes))
+	lo := 0
+	for i, s := range sizes {
+		shards[i] = stableSorted(all[lo : lo+s])
+		lo += s
+	}
+	return shards, stableSorted(all)
+}
+
+var mergeInputs = []struct {
+	name  string
+	sizes []int
+	keys  int
+}{
+	{"No inputs", nil, 1},
+	{"Empty inputs", []int{0, 0, 0}, 1},
+	{"Single input", []int{100}, 10},
+	{"Two inputs", []int{50, 70}, 5},
+	{"Uneven inputs", []int{1, 0, 300, 2, 45}, 20},
+	{"All equal keys", []int{30, 30, 30, 30}, 1},
+	{"Many inputs", slices.Repeat([]int{37}, 33), 100},
+	{"Large inputs", []int{40000, 1, 25000, 60000, 0, 30000}, 1000},
+}
+
+func TestMergeSorted(t *testing.T) {
+	comp := recordComparator{}
+
+	for _, tt := range mergeInputs {
+		shards, expected := sortedShards(tt.sizes, tt.keys)
+		original := make([][]record, len(shards))
+		for i, s := range shards {
+			original[i] = copySlice(s)
+		}
+
+		for _, workers := range []int{1, 8} {
+			t.Run(fmt.Sprintf("%s/workers=%d", tt.name, workers), func(t *testing.T) {
+				got := mergeSorted(comp, shards, workers)
+
+				if !slices.Equal(got, expected) {
+					t.Error("mergeSorted() result differs from stable sort")
+				}
+				if !reflect.DeepEqual(shards, original) {
+					t.Error("mergeSorted() changed its inputs")
+				}
+			})
+		}
+	}
+
+	got := MergeSorted(comparator.OrderedC[int]{}, []int{1, 4, 9}, []int{2, 3}, nil, []int{0, 10})
+	if expected := []int{0, 1, 2, 3, 4, 9, 10}; !slices.Equal(got, expected) {
+		t.Errorf("MergeSorted() = %v, want %v", got, expected)
+	}
+}
+
+func TestMergeSortedSeq(t *testing.T) {
+	comp := recordComparator{}
+
+	for _, tt := range mergeInputs {
+		t.Run(tt.name, func(t *testing.T) {
+			shards, expected := sortedShards(tt.sizes, tt.keys)
+			seqs := make([]iter.Seq[record], len(shards))
+			for i, s := range shards {
+				seqs[i] = slices.Values(s)
+			}
+
+			got := slices.Collect(MergeSortedSeq(comp, seqs...))
+
+			if !slices.Equal(got, expected) {
+				t.Error("MergeSortedSeq() result differs from stable sort")
+			}
+		})
+	}
+}
+
+// multiples возвращает бесконечную последовательность кратных n и считает,
+// сколько её элементов прочитано
+func multiples(n int, read *int) iter.Seq[int] {
+	return func(yield func(int) bool) {
+		for i := n; ; i += n {
+			*read++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+}
+
+func TestMergeSortedSeqLazy(t *testing.T) {
+	var read2, read3 int
+	seq := MergeSortedSeq(comparator.OrderedC[int]{}, multiples(2, &read2), multiples(3, &read3))
+
+	var got []int
+	for v := range seq {
+		got = append(got, v)
+		if len(got) == 8 {
+			break
+		}
+	}
+
+	if expected := []int{2, 3, 4, 6, 6, 8, 9, 10}; !slices.Equal(got, expected) {
+		t.Errorf("MergeSortedSeq() = %v, want %v", got, expected)
+	}
+	// Каждая последовательность прочитана не больше чем на элемент вперёд
+	if read2 > 6 || read3 > 4 {
+		t.Errorf("MergeSortedSeq() read %d and %d elements, want at most 6 and 4", read2, read3)
+	}
+}
+
+func TestLoserTree(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	for k := 0; k <= 17; k++ {
+		inputs := make([][]int, k)
+		var expected []int
+		for i := range inputs {
+			inputs[i] = GenerateRandomInts(i * 7 % 23)
+			slices.Sort(inputs[i])
+			expected = append(expected, inputs[i]...)
+		}
+		slices.Sort(expected)
+
+		pos := make([]int, k)
+		lt := newLoserTree(comp, k, func(i int) (int, bool) {
+			if pos[i] == len(inputs[i]) {
+				return 0, false
+			}
+			pos[i]++
+			return inputs[i][pos[i]-1], true
+		})
+		var got []int
+		for v, ok := lt.pop(); ok; v, ok = lt.pop() {
+			got = append(got, v)
+		}
+
+		if !slices.Equal(got, expected) {
+			t.Errorf("loser tree over %d inputs = %v, want %v", k, got, expected)
+		}
+	}
+}
+
+func BenchmarkMergeSorted(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	shards := make([][]int, 8)
+	for i := range shards {
+		shards[i] = GenerateRandomInts(125000)
+		slices.Sort(shards[i])
+	}
+
+	b.Run("ConcatAndSort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ParallelQuickSort(slices.Concat(shards...), comp)
+		}
+	})
+	b.Run("MergeSorted", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			MergeSorted(comp, shards...)
+		}
+	})
+	b.Run("MergeSortedSeq", func(b *testing.B) {
+		seqs := make([]iter.Seq[int], len(shards))
+		for i, s := range shards {
+			seqs[i] = slices.Values(s)
+		}
+		for i := 0; i < b.N; i++ {
+			for range MergeSortedSeq(comp, seqs...) {
+			}
+		}
+	})
+}
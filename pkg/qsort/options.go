@@ -0,0 +1,167 @@
+package qsort
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// PartitionScheme — схема разбиения быстрой сортировки
+type PartitionScheme int
+
+const (
+	// PartitionLomuto — разбиение Ломуто, как в ParallelQuickSort
+	PartitionLomuto PartitionScheme = iota
+	// PartitionThreeWay — трёхпутевое разбиение, как в ParallelQuickSort3Way
+	PartitionThreeWay
+	// PartitionPdq — pattern-defeating quicksort, как в ParallelPdqSort.
+	// Опорный элемент и порог сортировки вставками pdqsort выбирает сама
+	PartitionPdq
+	// PartitionDualPivot — два опорных элемента, как в ParallelDualPivotQuickSort.
+	// Опорные элементы берутся на границах третей массива
+	PartitionDualPivot
+)
+
+// Option — параметр Sort
+type Option func(*config)
+
+// config — параметры сортировки, собранные из Option
+type config struct {
+	threshold       int
+	maxGoroutines   int
+	insertionCutoff int
+	pivot           PivotStrategy
+	partition       PartitionScheme
+	stable          bool
+	scratch         ScratchBuffer
+	debug           bool
+}
+
+// newConfig возвращает параметры по умолчанию, изменённые opts
+func newConfig(opts []Option) config {
+	c := config{
+		threshold:       parallelThreshold,
+		maxGoroutines:   runtime.NumCPU(),
+		insertionCutoff: insertionSortThreshold,
+		pivot:           PivotMedianOfThree,
+		partition:       PartitionLomuto,
+		scratch:         ScratchFull,
+	}
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+// WithThreshold задаёт размер, начиная с которого подмассив делится между горутинами;
+// более короткие сортируются последовательно. По умолчанию — 1000
+func WithThreshold(threshold int) Option {
+	return func(c *config) { c.threshold = threshold }
+}
+
+// WithMaxGoroutines ограничивает число горутин, сортирующих массив.
+// По умолчанию и при n < 1 — runtime.NumCPU(); при n = 1 сортировка последовательная
+func WithMaxGoroutines(n int) Option {
+	return func(c *config) {
+		if n < 1 {
+			n = runtime.NumCPU()
+		}
+		c.maxGoroutines = n
+	}
+}
+
+// WithPivotStrategy задаёт выбор опорного элемента для PartitionLomuto и PartitionThreeWay.
+// По умолчанию — PivotMedianOfThree
+func WithPivotStrategy(s PivotStrategy) Option {
+	return func(c *config) { c.pivot = s }
+}
+
+// WithInsertionCutoff задаёт длину, до которой подмассивы досортировываются вставками,
+// для всех схем разбиения, кроме PartitionPdq. По умолчанию — 12, минимум — 1
+func WithInsertionCutoff(n int) Option {
+	return func(c *config) { c.insertionCutoff = max(n, 1) }
+}
+
+// WithPartition задаёт схему разбиения. По умолчанию — PartitionLomuto
+func WithPartition(p PartitionScheme) Option {
+	return func(c *config) { c.partition = p }
+}
+
+// WithStable включает устойчивую сортировку слиянием, как в ParallelStableSort.
+// Из остальных параметров ей важны только WithMaxGoroutines и WithScratchBuffer
+func WithStable() Option {
+	return func(c *config) { c.stable = true }
+}
+
+// WithScratchBuffer задаёт стратегию вспомогательной памяти устойчивой сортировки.
+// По умолчанию — ScratchFull
+func WithScratchBuffer(s ScratchBuffer) Option {
+	return func(c *config) { c.scratch = s }
+}
+
+// WithDebug включает проверку контракта компаратора: каждое сравнение проверяется
+// comparator.Checked, а результат — на упорядоченность. При первом нарушении
+// Sort паникует с описанием контрпримера. Сортировка замедляется в десятки раз
+func WithDebug() Option {
+	return func(c *config) { c.debug = true }
+}
+
+// Sort сортирует data. Без параметров работает как ParallelQuickSort.
+// Паника в comp, возникшая в одной из горутин, останавливает остальные
+// и повторяется в вызывающей горутине как *PanicError
+func Sort[T any](data []T, comp comparator.Comparator[T], opts ...Option) {
+	sortWith(context.Background(), data, comp, newConfig(opts))
+}
+
+// SortFunc — Sort с функцией сравнения, как в slices.SortFunc:
+// cmp(a, b) < 0 при a < b, > 0 при a > b и 0 при равенстве
+func SortFunc[T any](data []T, cmp func(a, b T) int, opts ...Option) {
+	Sort(data, comparator.ComparatorFunc[T](cmp), opts...)
+}
+
+// sortWith — общая реализация всех параллельных сортировок пакета.
+// Устойчивая сортировка отмену не поддерживает: прерванное слияние
+// оставило бы в data копии одних элементов вместо других
+func sortWith[T any](ctx context.Context, data []T, comp comparator.Comparator[T], c config) error {
+	if c.debug {
+		c.debug = false
+		if err := sortWith(ctx, data, comparator.Checked(comp), c); err != nil {
+			return err
+		}
+		checkSorted(data, comp)
+		return nil
+	}
+
+	if c.stable {
+		stableSort(data, comp, c.maxGoroutines, c.scratch)
+		return nil
+	}
+
+	choose := pivotFor[T](c.pivot)
+
+	var s scheme[T]
+	switch c.partition {
+	case PartitionThreeWay:
+		s = threeWayScheme(choose, c.insertionCutoff)
+	case PartitionPdq:
+		s = pdqScheme[T]()
+	case PartitionDualPivot:
+		return parallelDualPivotSort(ctx, data, comp, c.maxGoroutines, c.threshold, c.insertionCutoff)
+	default:
+		s = lomutoScheme(choose, c.insertionCutoff)
+	}
+	return parallelQuickSort(ctx, data, comp, c.maxGoroutines, c.threshold, s)
+}
+
+// checkSorted паникует, если data не упорядочен по comp.
+// С компаратором, соблюдающим контракт, так быть не может
+func checkSorted[T any](data []T, comp comparator.Comparator[T]) {
+	for i := 1; i < len(data); i++ {
+		if r := comp.Compare(data[i-1], data[i]); r > 0 {
+			panic(fmt.Errorf("qsort: result is not sorted at index %d: Compare(%v, %v) = %d, the comparator violates its contract",
+				i, data[i-1], data[i], r))
+		}
+	}
+}
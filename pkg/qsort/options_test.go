@@ -0,0 +1,249 @@
+package qsort
+
+import (
+	"cmp"
+	"errors"
+	"reflect"
+	"runtime"
+	"slices"
+	"sort"
+	"strconv"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+	"github.com/leonid-voroshilov/mm-qsort/pkg/generator"
+)
+
+func TestSortOptions(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	options := []struct {
+		name string
+		opts []Option
+	}{
+		{"Default", nil},
+		{"Sequential", []Option{WithMaxGoroutines(1)}},
+		{"Small threshold", []Option{WithMaxGoroutines(8), WithThreshold(16)}},
+		{"Ninther", []Option{WithMaxGoroutines(8), WithPivotStrategy(PivotNinther)}},
+		{"Random pivot", []Option{WithMaxGoroutines(8), WithPivotStrategy(PivotRandom)}},
+		{"Insertion cutoff 1", []Option{WithMaxGoroutines(8), WithInsertionCutoff(1)}},
+		{"Insertion cutoff 64", []Option{WithMaxGoroutines(8), WithInsertionCutoff(64)}},
+		{"Three way", []Option{WithMaxGoroutines(8), WithPartition(PartitionThreeWay), WithPivotStrategy(PivotNinther)}},
+		{"Pdq", []Option{WithMaxGoroutines(8), WithPartition(PartitionPdq)}},
+		{"Dual pivot", []Option{WithMaxGoroutines(8), WithPartition(PartitionDualPivot), WithInsertionCutoff(4)}},
+		{"Stable", []Option{WithMaxGoroutines(8), WithStable()}},
+		{"Stable half buffer", []Option{WithMaxGoroutines(8), WithStable(), WithScratchBuffer(ScratchHalf)}},
+	}
+
+	inputs := []struct {
+		name string
+		data []int
+	}{
+		{"Empty", []int{}},
+		{"Single element", []int{42}},
+		{"Random", GenerateRandomInts(100000)},
+		{"Sorted", generateSortedInts(20000)},
+		{"Reversed", generateReversedInts(20000)},
+		{"Few unique", generator.New(1).Ints(generator.FewUnique(3), 20000)},
+	}
+
+	for _, o := range options {
+		for _, in := range inputs {
+			t.Run(o.name+"_"+in.name, func(t *testing.T) {
+				data := copySlice(in.data)
+				expected := copySlice(in.data)
+				sort.Ints(expected)
+
+				Sort(data, comp, o.opts...)
+
+				if !reflect.DeepEqual(data, expected) {
+					t.Error("Sort() result doesn't match expected")
+				}
+			})
+		}
+	}
+}
+
+func TestSortWithStable(t *testing.T) {
+	for _, goroutines := range []int{1, 8} {
+		t.Run("goroutines_"+strconv.Itoa(goroutines), func(t *testing.T) {
+			data := generateRecords(50000, 100)
+			expected := stableSorted(data)
+
+			Sort(data, recordComparator{}, WithStable(), WithMaxGoroutines(goroutines))
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Error("Sort(WithStable()) didn't preserve order of equal elements")
+			}
+		})
+	}
+}
+
+func TestSortFunc(t *testing.T) {
+	// Результат совпадает с slices.SortStableFunc на той же функции сравнения
+	byKey := func(a, b record) int { return cmp.Compare(a.key, b.key) }
+
+	for _, goroutines := range []int{1, 8} {
+		t.Run("goroutines_"+strconv.Itoa(goroutines), func(t *testing.T) {
+			data := generateRecords(50000, 1000)
+			expected := copySlice(data)
+			slices.SortStableFunc(expected, byKey)
+
+			SortFunc(data, byKey, WithStable(), WithMaxGoroutines(goroutines))
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Error("SortFunc() result doesn't match slices.SortStableFunc")
+			}
+		})
+	}
+
+	t.Run("cmp.Compare", func(t *testing.T) {
+		data := GenerateRandomInts(20000)
+		expected := copySlice(data)
+		slices.Sort(expected)
+
+		SortFunc(data, cmp.Compare[int], WithMaxGoroutines(8))
+
+		if !reflect.DeepEqual(data, expected) {
+			t.Error("SortFunc(cmp.Compare) result doesn't match slices.Sort")
+		}
+	})
+}
+
+func TestSortWithDebug(t *testing.T) {
+	t.Run("Valid comparator", func(t *testing.T) {
+		data := GenerateRandomInts(5000)
+		expected := copySlice(data)
+		sort.Ints(expected)
+
+		Sort(data, comparator.OrderedC[int]{}, WithDebug(), WithMaxGoroutines(8), WithThreshold(100))
+
+		if !reflect.DeepEqual(data, expected) {
+			t.Error("Sort(WithDebug()) result doesn't match expected")
+		}
+	})
+
+	t.Run("SortFunc with difference", func(t *testing.T) {
+		// Функции в стиле slices.SortFunc могут возвращать любое число нужного знака
+		data := GenerateRandomInts(5000)
+		expected := copySlice(data)
+		sort.Ints(expected)
+
+		SortFunc(data, func(a, b int) int { return a - b }, WithDebug())
+
+		if !reflect.DeepEqual(data, expected) {
+			t.Error("SortFunc(a - b, WithDebug()) result doesn't match expected")
+		}
+	})
+
+	// Камень-ножницы-бумага: отношение не транзитивно
+	cyclic := comparator.ComparatorFunc[int](func(a, b int) int {
+		a, b = a%3, b%3
+		switch {
+		case a == b:
+			return 0
+		case (a+1)%3 == b:
+			return 1
+		}
+		return -1
+	})
+
+	for _, goroutines := range []int{1, 8} {
+		t.Run("Cyclic comparator_goroutines_"+strconv.Itoa(goroutines), func(t *testing.T) {
+			data := GenerateRandomInts(5000)
+
+			defer func() {
+				r := recover()
+				err, ok := r.(error)
+				if !ok {
+					t.Fatalf("Recovered %v, want an error", r)
+				}
+				var v comparator.Violation[int]
+				if !errors.As(err, &v) || v.Rule != comparator.Transitivity {
+					t.Errorf("Recovered %v, want transitivity violation", err)
+				}
+			}()
+
+			Sort(data, cyclic, WithDebug(), WithMaxGoroutines(goroutines), WithThreshold(100))
+		})
+	}
+}
+
+func TestCheckSorted(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+	checkSorted([]int{1, 2, 2, 3}, comp)
+
+	defer func() {
+		if r := recover(); r == nil {
+			t.Error("checkSorted() didn't panic on unsorted data")
+		}
+	}()
+	checkSorted([]int{1, 3, 2}, comp)
+}
+
+func TestNewConfig(t *testing.T) {
+	defaults := config{
+		threshold:       parallelThreshold,
+		maxGoroutines:   runtime.NumCPU(),
+		insertionCutoff: insertionSortThreshold,
+	}
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		expected config
+	}{
+		{"Defaults", nil, defaults},
+		{"Non-positive goroutines", []Option{WithMaxGoroutines(0)}, defaults},
+		{"Insertion cutoff below minimum", []Option{WithInsertionCutoff(-5)}, config{
+			threshold:       parallelThreshold,
+			maxGoroutines:   runtime.NumCPU(),
+			insertionCutoff: 1,
+		}},
+		{"Later option wins", []Option{WithThreshold(10), WithThreshold(20), WithMaxGoroutines(3)}, config{
+			threshold:       20,
+			maxGoroutines:   3,
+			insertionCutoff: insertionSortThreshold,
+		}},
+		{"Stable", []Option{WithStable(), WithScratchBuffer(ScratchNone)}, config{
+			threshold:       parallelThreshold,
+			maxGoroutines:   runtime.NumCPU(),
+			insertionCutoff: insertionSortThreshold,
+			stable:          true,
+			scratch:         ScratchNone,
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := newConfig(tt.opts); got != tt.expected {
+				t.Errorf("newConfig() = %+v, want %+v", got, tt.expected)
+			}
+		})
+	}
+}
+
+func BenchmarkSortPivotStrategy(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(1 << 20)
+
+	strategies := []struct {
+		name  string
+		pivot PivotStrategy
+	}{
+		{"MedianOfThree", PivotMedianOfThree},
+		{"Ninther", PivotNinther},
+		{"Random", PivotRandom},
+	}
+
+	for _, s := range strategies {
+		b.Run(s.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(data)
+				b.StartTimer()
+				Sort(testData, comp, WithPivotStrategy(s.pivot))
+				b.StopTimer()
+			}
+		})
+	}
+}
@@ -0,0 +1,74 @@
+package qsort
+
+import (
+	"fmt"
+	"runtime/debug"
+	"sync"
+	"sync/atomic"
+)
+
+// PanicError — паника, возникшая в горутине сортировки, как правило в Compare.
+// Параллельные сортировки перехватывают её в воркере, останавливают остальные
+// воркеры и повторяют panic с *PanicError в вызывающей горутине, а функции,
+// возвращающие ошибку, возвращают *PanicError
+type PanicError struct {
+	Value any    // значение, переданное в panic
+	Stack []byte // стек горутины в момент паники
+}
+
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("qsort: panic during sort: %v\n\n%s", e.Value, e.Stack)
+}
+
+// Unwrap возвращает исходное значение паники, если это ошибка
+func (e *PanicError) Unwrap() error {
+	err, _ := e.Value.(error)
+	return err
+}
+
+// newPanicError оборачивает значение, полученное от recover, вместе с текущим стеком.
+// Паника, уже обёрнутая в другой горутине, повторно не оборачивается,
+// чтобы сохранить стек, в котором она произошла
+func newPanicError(value any) *PanicError {
+	if e, ok := value.(*PanicError); ok {
+		return e
+	}
+	return &PanicError{Value: value, Stack: debug.Stack()}
+}
+
+// recoverPanic перехватывает панику и записывает её в *err как *PanicError.
+// Вызывается только через defer
+func recoverPanic(err *error) {
+	if r := recover(); r != nil {
+		*err = newPanicError(r)
+	}
+}
+
+// panicGroup ждёт завершения запущенных горутин, как sync.WaitGroup,
+// и повторяет в wait первую из возникших в них паник
+type panicGroup struct {
+	wg       sync.WaitGroup
+	panicked atomic.Pointer[PanicError]
+}
+
+// start запускает f в новой горутине
+func (g *panicGroup) start(f func()) {
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		defer func() {
+			if r := recover(); r != nil {
+				g.panicked.CompareAndSwap(nil, newPanicError(r))
+			}
+		}()
+		f()
+	}()
+}
+
+// wait ждёт завершения всех горутин
+func (g *panicGroup) wait() {
+	g.wg.Wait()
+	if e := g.panicked.Load(); e != nil {
+		panic(e)
+	}
+}
@@ -0,0 +1,199 @@
+package qsort
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+var errComparator = errors.New("comparator failed")
+
+// panickingComparator паникует со значением value на заданном по счёту сравнении
+type panickingComparator struct {
+	after       int64
+	value       any
+	comparisons atomic.Int64
+}
+
+func (c *panickingComparator) Compare(a, b int) int {
+	if c.comparisons.Add(1) == c.after {
+		panic(c.value)
+	}
+	return comparator.OrderedC[int]{}.Compare(a, b)
+}
+
+// recoverPanicError вызывает f и возвращает перехваченную панику
+func recoverPanicError(t *testing.T, f func()) (e *PanicError) {
+	t.Helper()
+	defer func() {
+		r := recover()
+		if r == nil {
+			t.Fatal("Expected panic, got none")
+		}
+		var ok bool
+		if e, ok = r.(*PanicError); !ok {
+			t.Fatalf("Recovered %T (%v), want *PanicError", r, r)
+		}
+	}()
+	f()
+	return nil
+}
+
+func TestSortPanicPropagation(t *testing.T) {
+	options := []struct {
+		name string
+		opts []Option
+	}{
+		{"Lomuto", nil},
+		{"Three way", []Option{WithPartition(PartitionThreeWay)}},
+		{"Pdq", []Option{WithPartition(PartitionPdq)}},
+		{"Dual pivot", []Option{WithPartition(PartitionDualPivot)}},
+		{"Stable", []Option{WithStable()}},
+	}
+
+	const n = 200000
+	original := GenerateRandomInts(n)
+
+	for _, o := range options {
+		// Паника в параллельном разбиении, на верхних уровнях и в последовательных листьях
+		for _, after := range []int64{100, n + 100, 10 * n} {
+			t.Run(o.name+"_after_"+strconv.FormatInt(after, 10), func(t *testing.T) {
+				data := copySlice(original)
+				comp := &panickingComparator{after: after, value: "boom"}
+
+				e := recoverPanicError(t, func() {
+					Sort(data, comp, append(o.opts, WithMaxGoroutines(8))...)
+				})
+
+				if e.Value != "boom" {
+					t.Errorf("PanicError.Value = %v, want boom", e.Value)
+				}
+				if !strings.Contains(string(e.Stack), "panickingComparator") {
+					t.Errorf("PanicError.Stack doesn't contain the comparator frame:\n%s", e.Stack)
+				}
+			})
+		}
+	}
+}
+
+func TestQuickSortContextPanic(t *testing.T) {
+	const n = 200000
+	original := GenerateRandomInts(n)
+
+	sorts := []struct {
+		name string
+		sort func(data []int, comp *panickingComparator) error
+	}{
+		{"Sequential", func(data []int, comp *panickingComparator) error {
+			return SequentialQuickSortContext(context.Background(), data, comp)
+		}},
+		{"Parallel", func(data []int, comp *panickingComparator) error {
+			return ParallelQuickSortContext(context.Background(), data, comp)
+		}},
+	}
+
+	for _, s := range sorts {
+		t.Run(s.name, func(t *testing.T) {
+			data := copySlice(original)
+			comp := &panickingComparator{after: 2 * n, value: errComparator}
+
+			err := s.sort(data, comp)
+
+			var e *PanicError
+			if !errors.As(err, &e) {
+				t.Fatalf("Sort returned %v, want *PanicError", err)
+			}
+			if !errors.Is(err, errComparator) {
+				t.Error("PanicError doesn't unwrap to the original error")
+			}
+			if !isPermutation(data, original) {
+				t.Error("Sort interrupted by panic lost or duplicated elements")
+			}
+		})
+	}
+}
+
+func TestSortContextPanicPermutation(t *testing.T) {
+	// После паники data остаётся перестановкой во всех режимах, кроме слияния
+	// через буфер: там прерванное слияние оставляет копии одних элементов вместо других
+	options := []struct {
+		name string
+		opts []Option
+	}{
+		{"Lomuto", nil},
+		{"Three way", []Option{WithPartition(PartitionThreeWay)}},
+		{"Pdq", []Option{WithPartition(PartitionPdq)}},
+		{"Dual pivot", []Option{WithPartition(PartitionDualPivot)}},
+		{"Stable without scratch", []Option{WithStable(), WithScratchBuffer(ScratchNone)}},
+	}
+
+	const n = 200000
+	original := GenerateRandomInts(n)
+
+	for _, o := range options {
+		for _, after := range []int64{100, n + 100, 10 * n} {
+			t.Run(o.name+"_after_"+strconv.FormatInt(after, 10), func(t *testing.T) {
+				data := copySlice(original)
+				comp := &panickingComparator{after: after, value: errComparator}
+
+				err := SortContext(context.Background(), data, comp, append(o.opts, WithMaxGoroutines(8))...)
+
+				if !errors.Is(err, errComparator) {
+					t.Fatalf("SortContext() = %v, want PanicError wrapping errComparator", err)
+				}
+				if !isPermutation(data, original) {
+					t.Error("SortContext interrupted by panic lost or duplicated elements")
+				}
+			})
+		}
+	}
+}
+
+func TestRunPoolPanicStopsWorkers(t *testing.T) {
+	// Задачи, оставшиеся в очереди после паники, не выполняются
+	var executed atomic.Int64
+
+	e := recoverPanicError(t, func() {
+		runPool(context.Background(), 1, func(w *worker) {
+			for i := 0; i < 100; i++ {
+				w.spawn(func(*worker) { executed.Add(1) })
+			}
+			panic("boom")
+		})
+	})
+
+	if e.Value != "boom" {
+		t.Errorf("PanicError.Value = %v, want boom", e.Value)
+	}
+	if got := executed.Load(); got != 0 {
+		t.Errorf("runPool executed %d tasks after panic, want 0", got)
+	}
+}
+
+func TestPanicGroup(t *testing.T) {
+	var g panicGroup
+	var executed atomic.Int64
+
+	for i := 0; i < 8; i++ {
+		g.start(func() {
+			executed.Add(1)
+			if i == 3 {
+				panic(i)
+			}
+		})
+	}
+
+	e := recoverPanicError(t, g.wait)
+
+	if e.Value != 3 {
+		t.Errorf("PanicError.Value = %v, want 3", e.Value)
+	}
+	if got := executed.Load(); got != 8 {
+		t.Errorf("panicGroup ran %d functions, want 8", got)
+	}
+}
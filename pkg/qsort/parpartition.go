@@ -0,0 +1,136 @@
+package qsort
+
+import (
+	"sort"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// parallelPartitionThreshold — массивы не короче этого размера parallelQuickSort
+// разбивает параллельно, если схема это поддерживает
+const parallelPartitionThreshold = 1 << 16
+
+// span — полуинтервал индексов [start, end)
+type span struct {
+	start, end int
+}
+
+// parallelPartition — параллельный аналог partition с тем же результатом:
+// слева элементы не больше опорного, справа — большие. Возвращает индекс опорного элемента.
+//
+// Массив делится на workers блоков, и каждая горутина разбивает свой блок по Ломуто.
+// После этого известно итоговое положение опорного элемента S — общее число малых
+// элементов. Большие элементы левее S и малые правее S стоят не на своих местах,
+// и их поровну; на этапе исправления горутины меняют их местами попарно,
+// поделив пары между собой. Опорный элемент выбирает choose
+func parallelPartition[T any](data []T, comp comparator.Comparator[T], workers int, choose pivotFunc[T]) int {
+	if len(data) <= 1 {
+		return 0
+	}
+
+	pivotIndex := choose(data, comp)
+	lastIndex := len(data) - 1
+	data[pivotIndex], data[lastIndex] = data[lastIndex], data[pivotIndex]
+	pivot := data[lastIndex]
+	body := data[:lastIndex]
+
+	workers = max(1, min(workers, len(body)))
+	blockSize := (len(body) + workers - 1) / workers
+
+	// Этап 1: каждая горутина разбивает свой блок
+	blocks := make([]span, 0, workers)
+	for start := 0; start < len(body); start += blockSize {
+		blocks = append(blocks, span{start, min(start+blockSize, len(body))})
+	}
+	small := make([]int, len(blocks))
+
+	var g panicGroup
+	for i, b := range blocks {
+		g.start(func() {
+			small[i] = partitionBlock(body[b.start:b.end], pivot, comp)
+		})
+	}
+	g.wait()
+
+	storeIndex := 0
+	for _, n := range small {
+		storeIndex += n
+	}
+
+	// Этап 2: находим отрезки, стоящие не на своей стороне от storeIndex
+	var misplacedLeft, misplacedRight []span
+	for i, b := range blocks {
+		boundary := b.start + small[i]
+		// Большие элементы блока левее storeIndex
+		if lo, hi := boundary, min(b.end, storeIndex); lo < hi {
+			misplacedLeft = append(misplacedLeft, span{lo, hi})
+		}
+		// Малые элементы блока правее storeIndex
+		if lo, hi := max(b.start, storeIndex), boundary; lo < hi {
+			misplacedRight = append(misplacedRight, span{lo, hi})
+		}
+	}
+
+	// Этап 3: меняем их местами попарно, поделив пары между горутинами
+	leftPrefix := spanPrefixSums(misplacedLeft)
+	rightPrefix := spanPrefixSums(misplacedRight)
+	misplaced := leftPrefix[len(leftPrefix)-1]
+	chunk := (misplaced + workers - 1) / workers
+
+	for from := 0; from < misplaced; from += chunk {
+		to := min(from+chunk, misplaced)
+		g.start(func() {
+			swapMisplaced(body, misplacedLeft, leftPrefix, misplacedRight, rightPrefix, from, to)
+		})
+	}
+	g.wait()
+
+	// Помещаем опорный элемент на правильную позицию
+	data[storeIndex], data[lastIndex] = data[lastIndex], data[storeIndex]
+
+	return storeIndex
+}
+
+// partitionBlock переносит в начало block элементы, не большие pivot, и возвращает их число
+func partitionBlock[T any](block []T, pivot T, comp comparator.Comparator[T]) int {
+	storeIndex := 0
+	for i := range block {
+		if comp.Compare(block[i], pivot) <= 0 {
+			block[i], block[storeIndex] = block[storeIndex], block[i]
+			storeIndex++
+		}
+	}
+	return storeIndex
+}
+
+// spanPrefixSums возвращает префиксные суммы длин отрезков; prefix[0] = 0
+func spanPrefixSums(spans []span) []int {
+	prefix := make([]int, len(spans)+1)
+	for i, s := range spans {
+		prefix[i+1] = prefix[i] + s.end - s.start
+	}
+	return prefix
+}
+
+// swapMisplaced меняет местами k-й элемент left и k-й элемент right для k из [from, to),
+// где элементы пронумерованы подряд по всем отрезкам
+func swapMisplaced[T any](data []T, left []span, leftPrefix []int, right []span, rightPrefix []int, from, to int) {
+	li := sort.SearchInts(leftPrefix, from+1) - 1
+	ri := sort.SearchInts(rightPrefix, from+1) - 1
+	l := left[li].start + from - leftPrefix[li]
+	r := right[ri].start + from - rightPrefix[ri]
+
+	for k := from; k < to; k++ {
+		if l == left[li].end {
+			li++
+			l = left[li].start
+		}
+		if r == right[ri].end {
+			ri++
+			r = right[ri].start
+		}
+		data[l], data[r] = data[r], data[l]
+		l++
+		r++
+	}
+}
@@ -0,0 +1,145 @@
+package qsort
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+	"github.com/leonid-voroshilov/mm-qsort/pkg/generator"
+)
+
+func TestParallelPartition(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	tests := []struct {
+		name string
+		data []int
+	}{
+		{"Single element", []int{42}},
+		{"Two elements", []int{2, 1}},
+		{"Small random", []int{3, 1, 4, 1, 5, 9, 2, 6}},
+		{"Random", GenerateRandomInts(10007)},
+		{"Sorted", generateSortedInts(10007)},
+		{"Reversed", generateReversedInts(10007)},
+		{"Few unique", generator.New(1).Ints(generator.FewUnique(3), 10007)},
+		{"All same", make([]int, 10007)},
+	}
+
+	for _, tt := range tests {
+		for _, workers := range []int{1, 3, 8, 64} {
+			t.Run(tt.name+"_workers_"+strconv.Itoa(workers), func(t *testing.T) {
+				data := copySlice(tt.data)
+
+				pivotIndex := parallelPartition(data, comp, workers, medianOfThree[int])
+				pivot := data[pivotIndex]
+
+				// Проверяем, что элементы слева не больше пивота
+				for i := 0; i < pivotIndex; i++ {
+					if comp.Compare(data[i], pivot) > 0 {
+						t.Fatalf("Element %v at index %d should be <= pivot %v", data[i], i, pivot)
+					}
+				}
+
+				// Проверяем, что элементы справа больше пивота
+				for i := pivotIndex + 1; i < len(data); i++ {
+					if comp.Compare(data[i], pivot) <= 0 {
+						t.Fatalf("Element %v at index %d should be > pivot %v", data[i], i, pivot)
+					}
+				}
+
+				// Проверяем, что разбиение — перестановка входа
+				sorted := copySlice(data)
+				sort.Ints(sorted)
+				expected := copySlice(tt.data)
+				sort.Ints(expected)
+				if !reflect.DeepEqual(sorted, expected) {
+					t.Error("parallelPartition() lost or duplicated elements")
+				}
+			})
+		}
+	}
+}
+
+func TestParallelPartitionMatchesPartition(t *testing.T) {
+	// Положение опорного элемента однозначно определяется входом
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(50000)
+	sequential := copySlice(data)
+
+	if got, want := parallelPartition(data, comp, 8, medianOfThree[int]), partition(sequential, comp); got != want {
+		t.Errorf("parallelPartition() = %d, partition() = %d", got, want)
+	}
+}
+
+func TestParallelQuickSortParallelPartition(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(4 * parallelPartitionThreshold)
+	expected := copySlice(data)
+	sort.Ints(expected)
+
+	parallelQuickSort(context.Background(), data, comp, 8, parallelThreshold, lomutoScheme(medianOfThree[int], insertionSortThreshold))
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Error("parallelQuickSort() with parallel partition doesn't match expected result")
+	}
+}
+
+func BenchmarkPartition(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(1 << 20)
+
+	b.Run("Sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			testData := copySlice(data)
+			b.StartTimer()
+			partition(testData, comp)
+			b.StopTimer()
+		}
+	})
+
+	for _, workers := range []int{1, 2, 4, 8} {
+		b.Run("Parallel_workers_"+strconv.Itoa(workers), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(data)
+				b.StartTimer()
+				parallelPartition(testData, comp, workers, medianOfThree[int])
+				b.StopTimer()
+			}
+		})
+	}
+}
+
+// Кривые ускорения parallelQuickSort до и после параллельного разбиения
+func BenchmarkParallelPartitionSpeedup(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(1 << 21)
+
+	before := lomutoScheme(medianOfThree[int], insertionSortThreshold)
+	before.parallelPartition = nil
+	after := lomutoScheme(medianOfThree[int], insertionSortThreshold)
+
+	for _, goroutines := range []int{1, 2, 4, 8} {
+		suffix := "_goroutines_" + strconv.Itoa(goroutines)
+
+		b.Run("Before"+suffix, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(data)
+				b.StartTimer()
+				parallelQuickSort(context.Background(), testData, comp, goroutines, parallelThreshold, before)
+				b.StopTimer()
+			}
+		})
+
+		b.Run("After"+suffix, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(data)
+				b.StartTimer()
+				parallelQuickSort(context.Background(), testData, comp, goroutines, parallelThreshold, after)
+				b.StopTimer()
+			}
+		})
+	}
+}
@@ -0,0 +1,373 @@
+package qsort
+
+import (
+	"math/bits"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// Параметры pdqsort взяты из оригинальной реализации Орсона Питерса
+const (
+	pdqInsertionSortThreshold = 24  // короче — сортируем вставками
+	pdqNintherThreshold       = 128 // длиннее — опорный элемент выбираем как псевдомедиану из девяти
+	pdqPartialInsertionLimit  = 8   // сколько перемещений допускает частичная сортировка вставками
+	pdqBlockSize              = 64  // размер блока смещений в блочном разбиении
+)
+
+// PdqSort — последовательная pattern-defeating quicksort.
+// Распознаёт уже упорядоченные участки, ломает неудачные шаблоны перемешиванием
+// и в худшем случае переходит на пирамидальную сортировку
+func PdqSort[T any](data []T, comp comparator.Comparator[T]) {
+	if len(data) <= 1 {
+		return
+	}
+
+	pdqSort(data, comp, nil)
+}
+
+// ParallelPdqSort — параллельная pdqsort: крупные подмассивы разбиваются блочным
+// разбиением pdqsort в общем пуле воркеров, а небольшие сортируются PdqSort
+func ParallelPdqSort[T any](data []T, comp comparator.Comparator[T]) {
+	Sort(data, comp, WithPartition(PartitionPdq))
+}
+
+// pdqScheme — движок pdqsort для parallelQuickSort. Задачи pdqSortTask передают
+// частям массива то же состояние, что и рекурсия pdqSortLoop, поэтому
+// параллельная версия тоже отделяет равные элементы и распознаёт упорядоченные участки
+func pdqScheme[T any]() scheme[T] {
+	return scheme[T]{
+		partition: pdqPartition[T],
+		sort:      pdqSort[T],
+		newTask: func(data []T, comp comparator.Comparator[T], threshold int) task {
+			return pdqSortTask(data, comp, threshold, 0, len(data), bits.Len(uint(len(data))), true)
+		},
+	}
+}
+
+// pdqSortTask возвращает задачу сортировки data[a:b] с тем же состоянием, что у
+// pdqSortLoop. Задача разбивает отрезок, кладёт большую часть в очередь воркера
+// и продолжает с меньшей; отрезки короче threshold сортируются pdqSortLoop.
+// Соседние с отрезком опорные элементы уже на своих местах и больше не меняются,
+// так что читать предшественника data[a-1] из другой горутины безопасно
+func pdqSortTask[T any](data []T, comp comparator.Comparator[T], threshold, a, b, badAllowed int, leftmost bool) task {
+	return func(w *worker) {
+		for {
+			size := b - a
+			if size < max(threshold, pdqInsertionSortThreshold) {
+				pdqSortLoop(data, comp, a, b, badAllowed, leftmost, w.pool.cancel)
+				return
+			}
+			if w.canceled() {
+				return
+			}
+
+			pdqChoosePivot(data, comp, a, b)
+
+			if !leftmost && comp.Compare(data[a-1], data[a]) >= 0 {
+				a = pdqPartitionLeft(data, comp, a, b) + 1
+				continue
+			}
+
+			pivotIndex, alreadyPartitioned := pdqPartitionRight(data, comp, a, b)
+
+			leftSize, rightSize := pivotIndex-a, b-pivotIndex-1
+			if leftSize < size/8 || rightSize < size/8 {
+				badAllowed--
+				if badAllowed == 0 {
+					heapSort(data[a:b], comp)
+					return
+				}
+				pdqBreakPatterns(data, a, pivotIndex, leftSize)
+				pdqBreakPatterns(data, pivotIndex+1, b, rightSize)
+			} else if alreadyPartitioned &&
+				pdqPartialInsertionSort(data, comp, a, pivotIndex) &&
+				pdqPartialInsertionSort(data, comp, pivotIndex+1, b) {
+				return
+			}
+
+			// Левая часть сохраняет предшественника отрезка, у правой им становится опорный элемент
+			if leftSize > rightSize {
+				w.spawn(pdqSortTask(data, comp, threshold, a, pivotIndex, badAllowed, leftmost))
+				a, leftmost = pivotIndex+1, false
+			} else {
+				if rightSize > 1 {
+					w.spawn(pdqSortTask(data, comp, threshold, pivotIndex+1, b, badAllowed, false))
+				}
+				b = pivotIndex
+			}
+		}
+	}
+}
+
+// pdqSort сортирует data, прекращая работу после закрытия канала отмены cancel
+func pdqSort[T any](data []T, comp comparator.Comparator[T], cancel <-chan struct{}) {
+	pdqSortLoop(data, comp, 0, len(data), bits.Len(uint(len(data))), true, cancel)
+}
+
+// pdqPartition выбирает опорный элемент так же, как pdqsort, и разбивает массив блочно
+func pdqPartition[T any](data []T, comp comparator.Comparator[T]) (int, int) {
+	if len(data) < pdqInsertionSortThreshold {
+		insertionSort(data, comp)
+		return 0, len(data)
+	}
+
+	pdqChoosePivot(data, comp, 0, len(data))
+	pivotIndex, _ := pdqPartitionRight(data, comp, 0, len(data))
+	return pivotIndex, pivotIndex + 1
+}
+
+// pdqSortLoop сортирует data[a:b]. badAllowed — сколько ещё сильно несбалансированных
+// разбиений допускается до перехода на пирамидальную сортировку. leftmost означает,
+// что слева от a нет элементов, иначе data[a-1] не больше любого элемента data[a:b].
+// Перед каждым разбиением проверяется канал отмены cancel
+func pdqSortLoop[T any](data []T, comp comparator.Comparator[T], a, b, badAllowed int, leftmost bool, cancel <-chan struct{}) {
+	for {
+		size := b - a
+		if size < pdqInsertionSortThreshold {
+			insertionSort(data[a:b], comp)
+			return
+		}
+		if canceled(cancel) {
+			return
+		}
+
+		pdqChoosePivot(data, comp, a, b)
+
+		// Если опорный элемент равен предшественнику, то все элементы, равные ему,
+		// уже на своих местах: отделяем их и продолжаем только с большими
+		if !leftmost && comp.Compare(data[a-1], data[a]) >= 0 {
+			a = pdqPartitionLeft(data, comp, a, b) + 1
+			continue
+		}
+
+		pivotIndex, alreadyPartitioned := pdqPartitionRight(data, comp, a, b)
+
+		leftSize, rightSize := pivotIndex-a, b-pivotIndex-1
+		if leftSize < size/8 || rightSize < size/8 {
+			// Сильный перекос: после нескольких таких разбиений переходим на heapSort,
+			// иначе перемешиваем элементы, чтобы сломать шаблон входных данных
+			badAllowed--
+			if badAllowed == 0 {
+				heapSort(data[a:b], comp)
+				return
+			}
+			pdqBreakPatterns(data, a, pivotIndex, leftSize)
+			pdqBreakPatterns(data, pivotIndex+1, b, rightSize)
+		} else if alreadyPartitioned &&
+			pdqPartialInsertionSort(data, comp, a, pivotIndex) &&
+			pdqPartialInsertionSort(data, comp, pivotIndex+1, b) {
+			// Массив был уже разбит, и обе части почти отсортированы
+			return
+		}
+
+		pdqSortLoop(data, comp, a, pivotIndex, badAllowed, leftmost, cancel)
+		a, leftmost = pivotIndex+1, false
+	}
+}
+
+// pdqChoosePivot выбирает опорный элемент data[a:b] и переставляет его в data[a]
+func pdqChoosePivot[T any](data []T, comp comparator.Comparator[T], a, b int) {
+	size := b - a
+	half := a + size/2
+
+	if size > pdqNintherThreshold {
+		sort3(data, comp, a, half, b-1)
+		sort3(data, comp, a+1, half-1, b-2)
+		sort3(data, comp, a+2, half+1, b-3)
+		sort3(data, comp, half-1, half, half+1)
+		data[a], data[half] = data[half], data[a]
+	} else {
+		sort3(data, comp, half, a, b-1)
+	}
+}
+
+// pdqBreakPatterns переставляет несколько элементов в начале и в конце data[a:b]
+func pdqBreakPatterns[T any](data []T, a, b, size int) {
+	if size < pdqInsertionSortThreshold {
+		return
+	}
+
+	quarter := size / 4
+	data[a], data[a+quarter] = data[a+quarter], data[a]
+	data[b-1], data[b-quarter] = data[b-quarter], data[b-1]
+
+	if size > pdqNintherThreshold {
+		data[a+1], data[a+quarter+1] = data[a+quarter+1], data[a+1]
+		data[a+2], data[a+quarter+2] = data[a+quarter+2], data[a+2]
+		data[b-2], data[b-quarter-1] = data[b-quarter-1], data[b-2]
+		data[b-3], data[b-quarter-2] = data[b-quarter-2], data[b-3]
+	}
+}
+
+// pdqPartitionRight разбивает data[a:b] относительно опорного элемента data[a]:
+// слева оказываются элементы меньше опорного, справа — не меньше.
+// Возвращает позицию опорного элемента и признак того, что массив уже был разбит.
+// Основной цикл — блочное разбиение (BlockQuicksort, Edelkamp и Weiss):
+// сначала в блоки записываются смещения элементов, стоящих не на своей стороне,
+// затем они обмениваются попарно
+func pdqPartitionRight[T any](data []T, comp comparator.Comparator[T], a, b int) (int, bool) {
+	pivot := data[a]
+	first, last := a, b
+
+	// Первый элемент не меньше опорного существует благодаря выбору медианы
+	for first++; comp.Compare(data[first], pivot) < 0; first++ {
+	}
+
+	// Первый с конца элемент меньше опорного; если слева от first ничего нет, поиск ограничен
+	if first-1 == a {
+		for first < last {
+			last--
+			if comp.Compare(data[last], pivot) < 0 {
+				break
+			}
+		}
+	} else {
+		for last--; comp.Compare(data[last], pivot) >= 0; last-- {
+		}
+	}
+
+	alreadyPartitioned := first >= last
+	if !alreadyPartitioned {
+		data[first], data[last] = data[last], data[first]
+		first++
+
+		var offsetsL, offsetsR [pdqBlockSize]uint8
+		baseL, baseR := first, last
+		numL, numR, startL, startR := 0, 0, 0, 0
+
+		for first < last {
+			// Определяем, сколько неразобранных элементов просматривать с каждой стороны
+			unknown := last - first
+			leftSplit, rightSplit := 0, 0
+			if numL == 0 {
+				leftSplit = unknown
+				if numR == 0 {
+					leftSplit = unknown / 2
+				}
+			}
+			if numR == 0 {
+				rightSplit = unknown - leftSplit
+			}
+
+			// Заполняем блоки смещений элементами, стоящими не на своей стороне
+			for i := 0; i < min(leftSplit, pdqBlockSize); i++ {
+				offsetsL[numL] = uint8(i)
+				if comp.Compare(data[first], pivot) >= 0 {
+					numL++
+				}
+				first++
+			}
+			for i := 0; i < min(rightSplit, pdqBlockSize); i++ {
+				last--
+				offsetsR[numR] = uint8(i + 1)
+				if comp.Compare(data[last], pivot) < 0 {
+					numR++
+				}
+			}
+
+			// Меняем местами пары элементов из двух блоков
+			num := min(numL, numR)
+			for i := 0; i < num; i++ {
+				l := baseL + int(offsetsL[startL+i])
+				r := baseR - int(offsetsR[startR+i])
+				data[l], data[r] = data[r], data[l]
+			}
+			numL, numR = numL-num, numR-num
+			startL, startR = startL+num, startR+num
+
+			if numL == 0 {
+				startL, baseL = 0, first
+			}
+			if numR == 0 {
+				startR, baseR = 0, last
+			}
+		}
+
+		// Всё, кроме оставшихся в одном из блоков элементов, уже разобрано
+		for numL > 0 {
+			numL--
+			last--
+			l := baseL + int(offsetsL[startL+numL])
+			data[l], data[last] = data[last], data[l]
+			first = last
+		}
+		for numR > 0 {
+			numR--
+			r := baseR - int(offsetsR[startR+numR])
+			data[r], data[first] = data[first], data[r]
+			first++
+			last = first
+		}
+	}
+
+	// Ставим опорный элемент на его место
+	pivotIndex := first - 1
+	data[a], data[pivotIndex] = data[pivotIndex], pivot
+
+	return pivotIndex, alreadyPartitioned
+}
+
+// pdqPartitionLeft разбивает data[a:b] так, что слева от опорного элемента data[a]
+// оказываются элементы не больше него, а справа — строго большие.
+// Используется, когда среди элементов много равных опорному
+func pdqPartitionLeft[T any](data []T, comp comparator.Comparator[T], a, b int) int {
+	pivot := data[a]
+	first, last := a, b
+
+	for last--; comp.Compare(pivot, data[last]) < 0; last-- {
+	}
+
+	if last+1 == b {
+		for first < last {
+			first++
+			if comp.Compare(pivot, data[first]) < 0 {
+				break
+			}
+		}
+	} else {
+		for first++; comp.Compare(pivot, data[first]) >= 0; first++ {
+		}
+	}
+
+	for first < last {
+		data[first], data[last] = data[last], data[first]
+		for last--; comp.Compare(pivot, data[last]) < 0; last-- {
+		}
+		for first++; comp.Compare(pivot, data[first]) >= 0; first++ {
+		}
+	}
+
+	data[a], data[last] = data[last], pivot
+
+	return last
+}
+
+// pdqPartialInsertionSort пытается досортировать data[a:b] вставками.
+// Сдаётся и возвращает false, если приходится переместить слишком много элементов
+func pdqPartialInsertionSort[T any](data []T, comp comparator.Comparator[T], a, b int) bool {
+	moved := 0
+	for i := a + 1; i < b; i++ {
+		j := i
+		for ; j > a && comp.Compare(data[j-1], data[j]) > 0; j-- {
+			data[j-1], data[j] = data[j], data[j-1]
+		}
+		moved += i - j
+		if moved > pdqPartialInsertionLimit {
+			return false
+		}
+	}
+	return true
+}
+
+// sort3 упорядочивает data[i], data[j], data[k]
+func sort3[T any](data []T, comp comparator.Comparator[T], i, j, k int) {
+	if comp.Compare(data[j], data[i]) < 0 {
+		data[i], data[j] = data[j], data[i]
+	}
+	if comp.Compare(data[k], data[j]) < 0 {
+		data[j], data[k] = data[k], data[j]
+		if comp.Compare(data[j], data[i]) < 0 {
+			data[i], data[j] = data[j], data[i]
+		}
+	}
+}
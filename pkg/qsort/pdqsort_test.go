@@ -0,0 +1,258 @@
+package qsort
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+func TestPdqSort(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	small := []testInput{
+		{"Empty slice", []int{}},
+		{"Single element", []int{42}},
+		{"Two elements reversed", []int{2, 1}},
+		{"Small random", []int{3, 1, 4, 1, 5, 9, 2, 6, 5}},
+	}
+
+	for _, size := range []int{100, 1000, 10000} {
+		for _, in := range patternInputs(size) {
+			in.name += "_" + strconv.Itoa(size)
+			small = append(small, in)
+		}
+	}
+
+	for _, tt := range small {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+			expected := copySlice(tt.data)
+			sort.Ints(expected)
+
+			PdqSort(data, comp)
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Errorf("PdqSort() result doesn't match expected (size %d)", len(data))
+			}
+		})
+	}
+}
+
+func TestParallelPdqSort(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	for _, tt := range patternInputs(50000) {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := copySlice(tt.data)
+			sort.Ints(expected)
+
+			data := copySlice(tt.data)
+			ParallelPdqSort(data, comp)
+			if !reflect.DeepEqual(data, expected) {
+				t.Error("ParallelPdqSort() result doesn't match expected")
+			}
+
+			// Явно задаём бюджет горутин, чтобы проверить параллельную рекурсию на любой машине
+			data = copySlice(tt.data)
+			parallelQuickSort(context.Background(), data, comp, 8, parallelThreshold, pdqScheme[int]())
+			if !reflect.DeepEqual(data, expected) {
+				t.Error("parallelQuickSort(pdqScheme) result doesn't match expected")
+			}
+		})
+	}
+}
+
+func TestPdqSortStrings(t *testing.T) {
+	comp := comparator.OrderedC[string]{}
+	data := []string{"zebra", "apple", "banana", "cherry", "date"}
+	expected := []string{"apple", "banana", "cherry", "date", "zebra"}
+
+	ParallelPdqSort(data, comp)
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("ParallelPdqSort(strings) = %v, want %v", data, expected)
+	}
+}
+
+func TestPdqSortAdversary(t *testing.T) {
+	const n = 4096
+
+	adversary := newAntiQuicksort(n)
+	PdqSort(generateSortedInts(n), adversary)
+
+	if limit := nLogN(n, 8); adversary.comparisons > limit {
+		t.Errorf("PdqSort made %d comparisons on adversarial input, want <= %d", adversary.comparisons, limit)
+	}
+}
+
+func TestPdqSortSortedIsLinear(t *testing.T) {
+	// На отсортированном входе pdqsort распознаёт уже разбитые участки
+	const n = 100000
+	counter := &countingComparator{}
+
+	PdqSort(generateSortedInts(n), counter)
+
+	if counter.count() > 4*n {
+		t.Errorf("PdqSort made %d comparisons on sorted input, want <= %d", counter.count(), 4*n)
+	}
+}
+
+func TestParallelPdqSortIsLinear(t *testing.T) {
+	// Параллельная версия тоже отделяет равные предшественнику элементы
+	// и распознаёт уже разбитые участки, а не отщепляет по одному элементу
+	const n = 100000
+	opts := []Option{WithMaxGoroutines(8), WithPartition(PartitionPdq)}
+
+	tests := []struct {
+		name string
+		data []int
+	}{
+		{"All same", make([]int, n)},
+		{"Sorted", generateSortedInts(n)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := &countingComparator{}
+			Sort(tt.data, counter, opts...)
+
+			if !isSorted(tt.data, comparator.OrderedC[int]{}) {
+				t.Fatal("Sort() result is not sorted")
+			}
+			if counter.count() > 4*n {
+				t.Errorf("Sort(PartitionPdq) made %d comparisons, want <= %d", counter.count(), 4*n)
+			}
+		})
+	}
+}
+
+func TestPdqPartitionRight(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	tests := []struct {
+		name               string
+		data               []int
+		alreadyPartitioned bool
+	}{
+		{"Random", GenerateRandomInts(1000), false},
+		{"Reversed", generateReversedInts(1000), false},
+		{"Sorted", generateSortedInts(1000), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+
+			pdqChoosePivot(data, comp, 0, len(data))
+			pivotIndex, alreadyPartitioned := pdqPartitionRight(data, comp, 0, len(data))
+			pivot := data[pivotIndex]
+
+			if alreadyPartitioned != tt.alreadyPartitioned {
+				t.Errorf("pdqPartitionRight() alreadyPartitioned = %v, want %v", alreadyPartitioned, tt.alreadyPartitioned)
+			}
+			for i := 0; i < pivotIndex; i++ {
+				if comp.Compare(data[i], pivot) >= 0 {
+					t.Fatalf("Element %v at index %d should be less than pivot %v", data[i], i, pivot)
+				}
+			}
+			for i := pivotIndex + 1; i < len(data); i++ {
+				if comp.Compare(data[i], pivot) < 0 {
+					t.Fatalf("Element %v at index %d should be >= pivot %v", data[i], i, pivot)
+				}
+			}
+
+			sorted := copySlice(data)
+			sort.Ints(sorted)
+			expected := copySlice(tt.data)
+			sort.Ints(expected)
+			if !reflect.DeepEqual(sorted, expected) {
+				t.Error("pdqPartitionRight() lost or duplicated elements")
+			}
+		})
+	}
+}
+
+func TestPdqPartitionLeft(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+	data := []int{5, 7, 5, 1, 9, 5, 3, 8, 5}
+
+	pivotIndex := pdqPartitionLeft(data, comp, 0, len(data))
+
+	for i := 0; i <= pivotIndex; i++ {
+		if data[i] > 5 {
+			t.Errorf("Element %v at index %d should be <= pivot 5", data[i], i)
+		}
+	}
+	for i := pivotIndex + 1; i < len(data); i++ {
+		if data[i] <= 5 {
+			t.Errorf("Element %v at index %d should be > pivot 5", data[i], i)
+		}
+	}
+}
+
+// Сравнение pdqsort с разбиением Ломуто
+func BenchmarkPdqVsLomuto(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+
+	for _, in := range patternInputs(100000) {
+		b.Run("Lomuto_"+in.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(in.data)
+				b.StartTimer()
+				SequentialQuickSort(testData, comp)
+				b.StopTimer()
+			}
+		})
+
+		b.Run("Pdq_"+in.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(in.data)
+				b.StartTimer()
+				PdqSort(testData, comp)
+				b.StopTimer()
+			}
+		})
+
+		b.Run("ParallelLomuto_"+in.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(in.data)
+				b.StartTimer()
+				ParallelQuickSort(testData, comp)
+				b.StopTimer()
+			}
+		})
+
+		b.Run("ParallelPdq_"+in.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(in.data)
+				b.StartTimer()
+				ParallelPdqSort(testData, comp)
+				b.StopTimer()
+			}
+		})
+	}
+}
+
+// Параллельная pdqsort на входах, где важно состояние разбиения
+func BenchmarkParallelPdqPatterns(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	opts := []Option{WithMaxGoroutines(8), WithPartition(PartitionPdq)}
+
+	for _, in := range patternInputs(1000000) {
+		if in.name != "All equal" && in.name != "Few unique" && in.name != "Sorted" {
+			continue
+		}
+		b.Run(in.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(in.data)
+				b.StartTimer()
+				Sort(testData, comp, opts...)
+				b.StopTimer()
+			}
+		})
+	}
+}
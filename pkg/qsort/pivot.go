@@ -0,0 +1,71 @@
+package qsort
+
+import (
+	"math/rand/v2"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// PivotStrategy — способ выбора опорного элемента
+type PivotStrategy int
+
+const (
+	// PivotMedianOfThree — медиана первого, среднего и последнего элементов
+	PivotMedianOfThree PivotStrategy = iota
+	// PivotNinther — псевдомедиана из девяти элементов (ninther Тьюки):
+	// медиана медиан трёх троек, равномерно разнесённых по массиву.
+	// На коротких массивах совпадает с PivotMedianOfThree
+	PivotNinther
+	// PivotRandom — случайный элемент. Защищает от входов, подобранных
+	// против детерминированного выбора, ценой худших разбиений в среднем
+	PivotRandom
+)
+
+// pivotFunc выбирает опорный элемент непустого массива и возвращает его индекс
+type pivotFunc[T any] func(data []T, comp comparator.Comparator[T]) int
+
+// pivotFor возвращает функцию выбора опорного элемента для стратегии s
+func pivotFor[T any](s PivotStrategy) pivotFunc[T] {
+	switch s {
+	case PivotNinther:
+		return ninther[T]
+	case PivotRandom:
+		return randomPivot[T]
+	default:
+		return medianOfThree[T]
+	}
+}
+
+// ninther выбирает псевдомедиану из девяти элементов
+func ninther[T any](data []T, comp comparator.Comparator[T]) int {
+	length := len(data)
+	if length < pdqNintherThreshold {
+		return medianOfThree(data, comp)
+	}
+
+	step, middle, last := length/8, length/2, length-1
+	return median3(data, comp,
+		median3(data, comp, 0, step, 2*step),
+		median3(data, comp, middle-step, middle, middle+step),
+		median3(data, comp, last-2*step, last-step, last),
+	)
+}
+
+// randomPivot выбирает случайный элемент
+func randomPivot[T any](data []T, _ comparator.Comparator[T]) int {
+	return rand.IntN(len(data))
+}
+
+// median3 возвращает тот из индексов i, j, k, элемент по которому — медиана трёх
+func median3[T any](data []T, comp comparator.Comparator[T], i, j, k int) int {
+	if comp.Compare(data[i], data[j]) > 0 {
+		i, j = j, i
+	}
+	if comp.Compare(data[j], data[k]) > 0 {
+		j = k
+		if comp.Compare(data[i], data[j]) > 0 {
+			j = i
+		}
+	}
+	return j
+}
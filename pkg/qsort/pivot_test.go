@@ -0,0 +1,61 @@
+package qsort
+
+import (
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+func TestMedian3(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	// Все перестановки трёх различных значений и случаи с повторами
+	tests := [][3]int{
+		{1, 2, 3}, {1, 3, 2}, {2, 1, 3}, {2, 3, 1}, {3, 1, 2}, {3, 2, 1},
+		{1, 1, 2}, {1, 2, 1}, {2, 1, 1}, {5, 5, 5},
+	}
+
+	for _, tt := range tests {
+		data := tt[:]
+		got := data[median3(data, comp, 0, 1, 2)]
+
+		sorted := copySlice(data)
+		insertionSort(sorted, comp)
+		if got != sorted[1] {
+			t.Errorf("median3(%v) = %d, want %d", data, got, sorted[1])
+		}
+	}
+}
+
+func TestPivotStrategies(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+	strategies := []struct {
+		name  string
+		pivot PivotStrategy
+	}{
+		{"MedianOfThree", PivotMedianOfThree},
+		{"Ninther", PivotNinther},
+		{"Random", PivotRandom},
+	}
+
+	for _, s := range strategies {
+		t.Run(s.name, func(t *testing.T) {
+			choose := pivotFor[int](s.pivot)
+			for _, size := range []int{1, 2, 3, 100, pdqNintherThreshold, 10000} {
+				data := GenerateRandomInts(size)
+				if index := choose(data, comp); index < 0 || index >= size {
+					t.Errorf("Pivot index %d out of range for size %d", index, size)
+				}
+			}
+		})
+	}
+}
+
+func TestNintherOnSorted(t *testing.T) {
+	// На отсортированном массиве псевдомедиана из девяти — точная медиана
+	data := generateSortedInts(1000)
+
+	if got := data[ninther(data, comparator.OrderedC[int]{})]; got != 500 {
+		t.Errorf("ninther() on sorted input chose %d, want 500", got)
+	}
+}
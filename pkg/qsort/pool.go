@@ -0,0 +1,209 @@
+package qsort
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+)
+
+// task — единица работы пула, например сортировка или слияние подмассива
+type task func(w *worker)
+
+// pool — пул воркеров с перехватом работы (work stealing).
+// Каждый воркер держит собственную двустороннюю очередь задач: новые подзадачи
+// кладёт в её конец и оттуда же берёт следующую, а когда очередь пуста — крадёт
+// самую старую (как правило, самую крупную) задачу из начала очереди другого
+// воркера. Так нагрузка выравнивается сама, даже если разбиения несбалансированы
+type pool struct {
+	workers []*worker
+	pending atomic.Int64    // задачи, которые созданы, но ещё не выполнены
+	wake    chan struct{}   // сигнал простаивающим воркерам о появлении задачи
+	done    chan struct{}   // закрывается, когда все задачи выполнены
+	cancel  <-chan struct{} // закрывается при отмене: оставшиеся задачи пропускаются
+	stop    context.CancelFunc
+
+	panicked atomic.Pointer[PanicError] // первая паника в задачах пула
+}
+
+// worker — воркер пула со своей очередью задач
+type worker struct {
+	pool  *pool
+	index int
+	tasks deque
+}
+
+// runPool запускает пул из workers воркеров, выполняет root и все порождённые
+// ею задачи и возвращает управление, когда они завершены. Один из воркеров
+// работает в вызывающей горутине. После отмены ctx задачи из очередей
+// не выполняются, а только снимаются с учёта, так что пул быстро завершается.
+// Паника в задаче так же останавливает пул, после чего runPool повторяет её
+// в вызывающей горутине как *PanicError
+func runPool(ctx context.Context, workers int, root task) {
+	ctx, stop := context.WithCancel(ctx)
+	defer stop()
+
+	workers = max(1, workers)
+	p := &pool{
+		workers: make([]*worker, workers),
+		wake:    make(chan struct{}, workers),
+		done:    make(chan struct{}),
+		cancel:  ctx.Done(),
+		stop:    stop,
+	}
+	for i := range p.workers {
+		p.workers[i] = &worker{pool: p, index: i}
+	}
+
+	p.pending.Store(1)
+	p.workers[0].tasks.push(root)
+
+	var wg sync.WaitGroup
+	for _, w := range p.workers[1:] {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			w.run()
+		}()
+	}
+	p.workers[0].run()
+	wg.Wait()
+
+	if e := p.panicked.Load(); e != nil {
+		panic(e)
+	}
+}
+
+// spawn добавляет задачу в очередь воркера, откуда её могут украсть другие
+func (w *worker) spawn(t task) {
+	w.pool.pending.Add(1)
+	w.tasks.push(t)
+
+	// Если буфер полон, сигналов уже хватает, чтобы разбудить всех воркеров
+	select {
+	case w.pool.wake <- struct{}{}:
+	default:
+	}
+}
+
+// fork выполняет left и right, причём right может быть украдена другим воркером,
+// и после завершения обеих вызывает then. Подзадачи сообщают о завершении,
+// вызывая переданное им продолжение
+func (w *worker) fork(left, right func(w *worker, done task), then task) {
+	j := &join{then: then}
+	j.pending.Store(2)
+
+	w.spawn(func(w *worker) { right(w, j.done) })
+	left(w, j.done)
+}
+
+// run выполняет задачи, пока в пуле есть незавершённая работа
+func (w *worker) run() {
+	for {
+		if t, ok := w.next(); ok {
+			if !w.canceled() {
+				w.execute(t)
+			}
+			if w.pool.pending.Add(-1) == 0 {
+				close(w.pool.done)
+			}
+			continue
+		}
+
+		select {
+		case <-w.pool.done:
+			return
+		case <-w.pool.wake:
+		}
+	}
+}
+
+// execute выполняет задачу, перехватывая панику: она запоминается
+// и останавливает пул
+func (w *worker) execute(t task) {
+	defer func() {
+		if r := recover(); r != nil {
+			w.pool.panicked.CompareAndSwap(nil, newPanicError(r))
+			w.pool.stop()
+		}
+	}()
+	t(w)
+}
+
+// canceled сообщает, отменена ли работа пула. Долгие задачи проверяют это
+// между разбиениями, чтобы не продолжать сортировку после отмены
+func (w *worker) canceled() bool {
+	return canceled(w.pool.cancel)
+}
+
+// next берёт задачу из своей очереди, а если она пуста — крадёт у других воркеров
+func (w *worker) next() (task, bool) {
+	if t, ok := w.tasks.pop(); ok {
+		return t, true
+	}
+
+	n := len(w.pool.workers)
+	for i := 1; i < n; i++ {
+		victim := w.pool.workers[(w.index+i)%n]
+		if t, ok := victim.tasks.steal(); ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// noop — продолжение, которому нечего делать
+func noop(*worker) {}
+
+// join вызывает then, когда все подзадачи вызовут done
+type join struct {
+	pending atomic.Int32
+	then    task
+}
+
+func (j *join) done(w *worker) {
+	if j.pending.Add(-1) == 0 {
+		j.then(w)
+	}
+}
+
+// deque — двусторонняя очередь задач воркера
+type deque struct {
+	mu    sync.Mutex
+	tasks []task
+}
+
+// push кладёт задачу в конец очереди
+func (d *deque) push(t task) {
+	d.mu.Lock()
+	d.tasks = append(d.tasks, t)
+	d.mu.Unlock()
+}
+
+// pop забирает задачу из конца очереди (используется владельцем)
+func (d *deque) pop() (task, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	n := len(d.tasks)
+	if n == 0 {
+		return nil, false
+	}
+	t := d.tasks[n-1]
+	d.tasks[n-1] = nil
+	d.tasks = d.tasks[:n-1]
+	return t, true
+}
+
+// steal забирает задачу из начала очереди (используется другими воркерами)
+func (d *deque) steal() (task, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if len(d.tasks) == 0 {
+		return nil, false
+	}
+	t := d.tasks[0]
+	d.tasks[0] = nil
+	d.tasks = d.tasks[1:]
+	return t, true
+}
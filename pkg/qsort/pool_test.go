@@ -0,0 +1,124 @@
+package qsort
+
+import (
+	"context"
+	"reflect"
+	"sync/atomic"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+func TestDeque(t *testing.T) {
+	var d deque
+	var order []int
+
+	for i := 0; i < 3; i++ {
+		d.push(func(*worker) { order = append(order, i) })
+	}
+
+	// Владелец забирает с конца, вор — с начала
+	if task, ok := d.pop(); ok {
+		task(nil)
+	}
+	if task, ok := d.steal(); ok {
+		task(nil)
+	}
+	if task, ok := d.pop(); ok {
+		task(nil)
+	}
+	if _, ok := d.pop(); ok {
+		t.Error("pop() from empty deque returned a task")
+	}
+	if _, ok := d.steal(); ok {
+		t.Error("steal() from empty deque returned a task")
+	}
+
+	if expected := []int{2, 0, 1}; !reflect.DeepEqual(order, expected) {
+		t.Errorf("Tasks executed in order %v, want %v", order, expected)
+	}
+}
+
+func TestRunPoolRunsAllTasks(t *testing.T) {
+	var executed atomic.Int64
+
+	// Дерево задач глубины 10: каждая порождает две дочерние
+	var spawnTree func(depth int) task
+	spawnTree = func(depth int) task {
+		return func(w *worker) {
+			executed.Add(1)
+			if depth > 0 {
+				w.spawn(spawnTree(depth - 1))
+				w.spawn(spawnTree(depth - 1))
+			}
+		}
+	}
+
+	runPool(context.Background(), 4, spawnTree(10))
+
+	if got, want := executed.Load(), int64(1<<11-1); got != want {
+		t.Errorf("runPool executed %d tasks, want %d", got, want)
+	}
+}
+
+func TestRunPoolStealsWork(t *testing.T) {
+	// Корневая задача ждёт, пока порождённую ею задачу выполнит другой воркер:
+	// без перехвата работы тест бы завис
+	stolen := make(chan int)
+
+	runPool(context.Background(), 2, func(w *worker) {
+		w.spawn(func(thief *worker) { stolen <- thief.index })
+		if index := <-stolen; index == w.index {
+			t.Error("Spawned task was executed by its owner")
+		}
+	})
+}
+
+func TestForkJoin(t *testing.T) {
+	var left, right, then atomic.Bool
+
+	runPool(context.Background(), 4, func(w *worker) {
+		w.fork(
+			func(w *worker, done task) { left.Store(true); done(w) },
+			func(w *worker, done task) { right.Store(true); done(w) },
+			func(w *worker) {
+				if !left.Load() || !right.Load() {
+					t.Error("then was called before both subtasks finished")
+				}
+				then.Store(true)
+			},
+		)
+	})
+
+	if !then.Load() {
+		t.Error("then was not called")
+	}
+}
+
+// firstElementPartition — разбиение Ломуто с первым элементом в роли опорного
+func firstElementPartition(data []int, comp comparator.Comparator[int]) (int, int) {
+	lastIndex := len(data) - 1
+	data[0], data[lastIndex] = data[lastIndex], data[0]
+	pivotIndex := partitionBlock(data[:lastIndex], data[lastIndex], comp)
+	data[pivotIndex], data[lastIndex] = data[lastIndex], data[pivotIndex]
+	return pivotIndex, pivotIndex + 1
+}
+
+func TestQuickSortTaskUnbalanced(t *testing.T) {
+	// На отсортированном входе первый элемент — минимум, и каждое разбиение
+	// отщепляет один элемент. Сортировка должна остаться корректной, а число
+	// разбиений в пуле — ограниченным
+	comp := &countingComparator{}
+	data := generateSortedInts(100000)
+	expected := copySlice(data)
+
+	skewed := scheme[int]{partition: firstElementPartition, sort: introSortWith(firstElementPartition, insertionSortThreshold)}
+	parallelQuickSort(context.Background(), data, comp, 4, 100, skewed)
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Error("parallelQuickSort() on unbalanced partitions doesn't match expected result")
+	}
+	if limit := nLogN(len(data), 8); comp.count() > limit {
+		t.Errorf("parallelQuickSort() made %d comparisons on unbalanced partitions, want <= %d", comp.count(), limit)
+	}
+}
@@ -0,0 +1,284 @@
+package qsort
+
+import (
+	"cmp"
+	"context"
+	"runtime"
+	"unsafe"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// Integer — целые типы, которые умеет сортировать RadixSort
+type Integer interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 | ~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 | ~uintptr
+}
+
+// radixThreshold — массивы короче этого размера поразрядные сортировки
+// сортируют сравнениями: подсчёт 256 корзин для них дороже самой сортировки
+const radixThreshold = 64
+
+// RadixSort — последовательная поразрядная сортировка целых чисел (LSD):
+// по одному проходу подсчётом на каждый байт, O(n) на проход
+func RadixSort[T Integer](data []T) {
+	radixSort(data, 1)
+}
+
+// ParallelRadixSort — параллельная поразрядная сортировка целых чисел (LSD).
+// На каждом проходе горутины считают гистограммы своих блоков, а затем
+// раскладывают элементы блоков по корзинам в общий буфер на n элементов
+func ParallelRadixSort[T Integer](data []T) {
+	radixSort(data, runtime.NumCPU())
+}
+
+func radixSort[T Integer](data []T, workers int) {
+	if len(data) < radixThreshold {
+		insertionSort(data, comparator.OrderedC[T]{})
+		return
+	}
+
+	// У знаковых типов инвертируем знаковый бит, чтобы отрицательные числа
+	// шли раньше положительных при сравнении как беззнаковых
+	var zero T
+	bits := 8 * uint(unsafe.Sizeof(zero))
+	var flip uint64
+	if ^zero < 0 {
+		flip = 1 << (bits - 1)
+	}
+
+	parts := chunks(len(data), workers, parallelThreshold)
+	counts := make([][256]int, len(parts))
+	src, dst := data, make([]T, len(data))
+
+	for shift := uint(0); shift < bits; shift += 8 {
+		parallelFor(parts, func(i int, s span) {
+			c := &counts[i]
+			*c = [256]int{}
+			for _, v := range src[s.start:s.end] {
+				c[byte((uint64(v)^flip)>>shift)]++
+			}
+		})
+
+		// Если у всех элементов этот байт одинаков, проход ничего не меняет
+		if radixTrivial(counts, len(data)) {
+			continue
+		}
+
+		// Корзина d блока i начинается после всех меньших корзин
+		// и после корзины d предыдущих блоков
+		offset := 0
+		for d := range 256 {
+			for i := range counts {
+				n := counts[i][d]
+				counts[i][d] = offset
+				offset += n
+			}
+		}
+
+		parallelFor(parts, func(i int, s span) {
+			c := &counts[i]
+			for _, v := range src[s.start:s.end] {
+				d := byte((uint64(v) ^ flip) >> shift)
+				dst[c[d]] = v
+				c[d]++
+			}
+		})
+		src, dst = dst, src
+	}
+
+	if &src[0] != &data[0] {
+		copy(data, src)
+	}
+}
+
+// radixTrivial сообщает, попали ли все n элементов в одну корзину
+func radixTrivial(counts [][256]int, n int) bool {
+	for d := range 256 {
+		total := 0
+		for i := range counts {
+			total += counts[i][d]
+		}
+		if total != 0 {
+			return total == n
+		}
+	}
+	return true
+}
+
+// RadixSortStrings — последовательная поразрядная сортировка строк или байтовых срезов
+// от старшего байта (MSD) в побайтовом порядке, как у StringC и bytes.Compare.
+// Элементы раскладываются по корзинам очередного байта, и каждая корзина
+// сортируется так же по следующему байту
+func RadixSortStrings[S ~string | ~[]byte](data []S) {
+	msdSort(data, make([]S, len(data)), 0, nil)
+}
+
+// ParallelRadixSortStrings — параллельная MSD-сортировка строк или байтовых срезов:
+// крупные корзины сортируются отдельными задачами пула
+func ParallelRadixSortStrings[S ~string | ~[]byte](data []S) {
+	parallelRadixSortStrings(data, runtime.NumCPU())
+}
+
+func parallelRadixSortStrings[S ~string | ~[]byte](data []S, workers int) {
+	if len(data) < parallelThreshold || workers <= 1 {
+		RadixSortStrings(data)
+		return
+	}
+
+	buf := make([]S, len(data))
+	runPool(context.Background(), workers, func(w *worker) {
+		msdSort(data, buf, 0, w)
+	})
+}
+
+// msdSort сортирует data по байтам начиная с depth; все строки data совпадают
+// до depth. buf — вспомогательный буфер той же длины. Если w не nil, корзины
+// не короче parallelThreshold сортируются отдельными задачами пула
+func msdSort[S ~string | ~[]byte](data, buf []S, depth int, w *worker) {
+	for len(data) >= radixThreshold {
+		// Корзина 0 — строки, закончившиеся до depth, корзина b+1 — строки с байтом b
+		var counts [257]int
+		for _, s := range data {
+			counts[msdDigit(s, depth)]++
+		}
+
+		if first := msdDigit(data[0], depth); counts[first] == len(data) {
+			// Все строки в одной корзине: сразу переходим к следующему байту
+			if first == 0 {
+				return
+			}
+			depth++
+			continue
+		}
+
+		var starts [258]int
+		for d, n := range counts {
+			starts[d+1] = starts[d] + n
+		}
+
+		next := starts
+		for _, s := range data {
+			d := msdDigit(s, depth)
+			buf[next[d]] = s
+			next[d]++
+		}
+		copy(data, buf)
+
+		// Строки корзины 0 равны между собой, остальные сортируем по следующему байту
+		for d := 1; d < 257; d++ {
+			lo, hi := starts[d], starts[d+1]
+			if hi-lo < 2 {
+				continue
+			}
+			if w != nil && hi-lo >= parallelThreshold {
+				w.spawn(func(w *worker) { msdSort(data[lo:hi], buf[lo:hi], depth+1, w) })
+				continue
+			}
+			msdSort(data[lo:hi], buf[lo:hi], depth+1, w)
+		}
+		return
+	}
+
+	suffixInsertionSort(data, depth)
+}
+
+// msdDigit возвращает номер корзины s для байта depth
+func msdDigit[S ~string | ~[]byte](s S, depth int) int {
+	if depth >= len(s) {
+		return 0
+	}
+	return int(s[depth]) + 1
+}
+
+// suffixInsertionSort сортирует вставками строки, совпадающие до depth
+func suffixInsertionSort[S ~string | ~[]byte](data []S, depth int) {
+	for i := 1; i < len(data); i++ {
+		for j := i; j > 0 && compareFrom(data[j-1], data[j], depth) > 0; j-- {
+			data[j], data[j-1] = data[j-1], data[j]
+		}
+	}
+}
+
+// compareFrom побайтово сравнивает a[depth:] и b[depth:]
+func compareFrom[S ~string | ~[]byte](a, b S, depth int) int {
+	for i := depth; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case len(a) < len(b):
+		return -1
+	case len(a) > len(b):
+		return 1
+	}
+	return 0
+}
+
+// AutoSort сортирует data, выбирая алгоритм по типу элементов и компаратору:
+// срезы встроенных целых типов и строк в естественном порядке (comp — comparator.OrderedC,
+// comparator.IntC или comparator.StringC) сортируются поразрядно, остальные — Sort
+// с параметрами opts. Из opts поразрядной сортировке важен только WithMaxGoroutines;
+// с WithDebug всегда используется Sort
+func AutoSort[T any](data []T, comp comparator.Comparator[T], opts ...Option) {
+	c := newConfig(opts)
+	if !c.debug && radixSortAny(data, comp, c.maxGoroutines) {
+		return
+	}
+	sortWith(context.Background(), data, comp, c)
+}
+
+// radixSortAny сортирует data поразрядно, если это срез встроенного целого типа
+// или строк, а comp задаёт естественный порядок, и сообщает, удалось ли
+func radixSortAny[T any](data []T, comp comparator.Comparator[T], workers int) bool {
+	switch d := any(data).(type) {
+	case []int:
+		return radixSortIfNatural(d, comp, workers)
+	case []int8:
+		return radixSortIfNatural(d, comp, workers)
+	case []int16:
+		return radixSortIfNatural(d, comp, workers)
+	case []int32:
+		return radixSortIfNatural(d, comp, workers)
+	case []int64:
+		return radixSortIfNatural(d, comp, workers)
+	case []uint:
+		return radixSortIfNatural(d, comp, workers)
+	case []uint8:
+		return radixSortIfNatural(d, comp, workers)
+	case []uint16:
+		return radixSortIfNatural(d, comp, workers)
+	case []uint32:
+		return radixSortIfNatural(d, comp, workers)
+	case []uint64:
+		return radixSortIfNatural(d, comp, workers)
+	case []uintptr:
+		return radixSortIfNatural(d, comp, workers)
+	case []string:
+		if naturalOrder[string](comp) {
+			parallelRadixSortStrings(d, workers)
+			return true
+		}
+	}
+	return false
+}
+
+func radixSortIfNatural[E Integer](data []E, comp any, workers int) bool {
+	if !naturalOrder[E](comp) {
+		return false
+	}
+	radixSort(data, workers)
+	return true
+}
+
+// naturalOrder сообщает, задаёт ли comp естественный порядок E
+func naturalOrder[E cmp.Ordered](comp any) bool {
+	switch comp.(type) {
+	case comparator.OrderedC[E], comparator.IntC, comparator.StringC:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,230 @@
+package qsort
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"slices"
+	"strings"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+type temperature int16
+
+// testRadixSort проверяет обе поразрядные сортировки на срезах T разных размеров.
+// Значения — случайные биты, так что встречаются и крайние значения типа
+func testRadixSort[T Integer](t *testing.T, extremes ...T) {
+	t.Run(fmt.Sprintf("%T", extremes[0]), func(t *testing.T) {
+		rng := rand.New(rand.NewPCG(1, 2))
+
+		for _, size := range []int{0, 1, 2, radixThreshold - 1, radixThreshold, 1000, 100000} {
+			data := make([]T, size)
+			for i := range data {
+				data[i] = T(rng.Uint64())
+			}
+			if size > len(extremes) {
+				copy(data, extremes)
+				rng.Shuffle(size, func(i, j int) { data[i], data[j] = data[j], data[i] })
+			}
+			expected := slices.Clone(data)
+			slices.Sort(expected)
+
+			for name, sort := range map[string]func([]T){
+				"RadixSort":         RadixSort[T],
+				"ParallelRadixSort": ParallelRadixSort[T],
+				"8 goroutines":      func(data []T) { radixSort(data, 8) },
+			} {
+				got := slices.Clone(data)
+				sort(got)
+				if !slices.Equal(got, expected) {
+					t.Errorf("%s of %d elements is wrong", name, size)
+				}
+			}
+		}
+	})
+}
+
+func TestRadixSort(t *testing.T) {
+	testRadixSort[int](t, math.MinInt, -1, 0, math.MaxInt)
+	testRadixSort[int8](t, math.MinInt8, -1, 0, math.MaxInt8)
+	testRadixSort[int16](t, math.MinInt16, -1, 0, math.MaxInt16)
+	testRadixSort[int32](t, math.MinInt32, -1, 0, math.MaxInt32)
+	testRadixSort[int64](t, math.MinInt64, -1, 0, math.MaxInt64)
+	testRadixSort[uint](t, 0, 1, math.MaxUint)
+	testRadixSort[uint8](t, 0, 1, math.MaxUint8)
+	testRadixSort[uint16](t, 0, 1, math.MaxUint16)
+	testRadixSort[uint32](t, 0, 1, math.MaxUint32)
+	testRadixSort[uint64](t, 0, 1, math.MaxUint64)
+	testRadixSort[uintptr](t, 0, 1, math.MaxUint64)
+	testRadixSort[temperature](t, math.MinInt16, -273, 0, math.MaxInt16)
+}
+
+func TestRadixSortSameHighBytes(t *testing.T) {
+	// Старшие байты одинаковы, и их проходы пропускаются
+	data := GenerateRandomInts(10000)
+	expected := slices.Clone(data)
+	slices.Sort(expected)
+
+	ParallelRadixSort(data)
+
+	if !slices.Equal(data, expected) {
+		t.Error("ParallelRadixSort() result is wrong")
+	}
+}
+
+func randomStrings(rng *rand.Rand, size int) []string {
+	prefixes := []string{"", "a", "ab", "abc", "b", "приве", "\x00", "\xff"}
+	data := make([]string, size)
+	for i := range data {
+		var sb strings.Builder
+		sb.WriteString(prefixes[rng.IntN(len(prefixes))])
+		for range rng.IntN(6) {
+			sb.WriteByte("ab\x00\xffz"[rng.IntN(5)])
+		}
+		data[i] = sb.String()
+	}
+	return data
+}
+
+func TestRadixSortStrings(t *testing.T) {
+	rng := rand.New(rand.NewPCG(3, 4))
+
+	tests := []struct {
+		name string
+		data []string
+	}{
+		{"Empty slice", []string{}},
+		{"Single element", []string{"x"}},
+		{"Small", []string{"zebra", "apple", "", "app", "apple", "b"}},
+		{"Equal strings", slices.Repeat([]string{"same"}, 500)},
+		{"Empty strings", slices.Repeat([]string{""}, 500)},
+		{"Long common prefix", []string{strings.Repeat("x", 1000) + "b", strings.Repeat("x", 1000) + "a", strings.Repeat("x", 1000)}},
+		{"Random", randomStrings(rng, 5000)},
+		{"Large random", randomStrings(rng, 200000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := slices.Clone(tt.data)
+			slices.Sort(expected)
+
+			got := slices.Clone(tt.data)
+			RadixSortStrings(got)
+			if !slices.Equal(got, expected) {
+				t.Error("RadixSortStrings() result is wrong")
+			}
+
+			got = slices.Clone(tt.data)
+			parallelRadixSortStrings(got, 8)
+			if !slices.Equal(got, expected) {
+				t.Error("parallelRadixSortStrings() result is wrong")
+			}
+
+			byteSlices := make([][]byte, len(tt.data))
+			for i, s := range tt.data {
+				byteSlices[i] = []byte(s)
+			}
+			ParallelRadixSortStrings(byteSlices)
+			if !slices.IsSortedFunc(byteSlices, bytes.Compare) {
+				t.Error("ParallelRadixSortStrings([][]byte) result is not sorted")
+			}
+		})
+	}
+}
+
+func TestAutoSort(t *testing.T) {
+	tests := []struct {
+		name  string
+		radix func() bool
+	}{
+		{"int OrderedC", func() bool { return radixSortAny([]int{2, 1}, comparator.OrderedC[int]{}, 1) }},
+		{"int IntC", func() bool { return radixSortAny([]int{2, 1}, comparator.IntC{}, 1) }},
+		{"uint8 OrderedC", func() bool { return radixSortAny([]uint8{2, 1}, comparator.OrderedC[uint8]{}, 1) }},
+		{"string StringC", func() bool { return radixSortAny([]string{"b", "a"}, comparator.StringC{}, 1) }},
+		{"string OrderedC", func() bool { return radixSortAny([]string{"b", "a"}, comparator.OrderedC[string]{}, 1) }},
+	}
+	fallback := []struct {
+		name  string
+		radix func() bool
+	}{
+		{"reversed order", func() bool { return radixSortAny([]int{2, 1}, comparator.Reverse[int](comparator.IntC{}), 1) }},
+		{"function comparator", func() bool { return radixSortAny([]int{2, 1}, comparator.ComparatorFunc[int](cmp.Compare[int]), 1) }},
+		{"floats", func() bool { return radixSortAny([]float64{2, 1}, comparator.OrderedC[float64]{}, 1) }},
+		{"named integer type", func() bool { return radixSortAny([]temperature{2, 1}, comparator.OrderedC[temperature]{}, 1) }},
+		{"natural string order", func() bool { return radixSortAny([]string{"b", "a"}, comparator.NaturalStringC{}, 1) }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !tt.radix() {
+				t.Error("radixSortAny() = false, want radix sort")
+			}
+		})
+	}
+	for _, tt := range fallback {
+		t.Run(tt.name, func(t *testing.T) {
+			if tt.radix() {
+				t.Error("radixSortAny() = true, want fallback to Sort")
+			}
+		})
+	}
+
+	t.Run("sorts with radix", func(t *testing.T) {
+		data := GenerateRandomInts(50000)
+		expected := slices.Clone(data)
+		slices.Sort(expected)
+		AutoSort(data, comparator.OrderedC[int]{})
+		if !slices.Equal(data, expected) {
+			t.Error("AutoSort() result is wrong")
+		}
+	})
+	t.Run("sorts with fallback", func(t *testing.T) {
+		data := GenerateRandomInts(50000)
+		comp := comparator.Reverse[int](comparator.OrderedC[int]{})
+		AutoSort(data, comp, WithMaxGoroutines(4))
+		if !isSorted(data, comp) {
+			t.Error("AutoSort() with Reverse is not sorted")
+		}
+	})
+}
+
+func BenchmarkRadixSort(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(1000000)
+
+	sorts := []struct {
+		name string
+		sort func([]int)
+	}{
+		{"ParallelQuickSort", func(d []int) { ParallelQuickSort(d, comp) }},
+		{"RadixSort", RadixSort[int]},
+		{"ParallelRadixSort", ParallelRadixSort[int]},
+	}
+	for _, s := range sorts {
+		b.Run(s.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(data)
+				s.sort(testData)
+			}
+		})
+	}
+}
+
+func BenchmarkRadixSortStrings(b *testing.B) {
+	data := randomStrings(rand.New(rand.NewPCG(5, 6)), 200000)
+
+	b.Run("ParallelQuickSort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ParallelQuickSort(copySlice(data), comparator.StringC{})
+		}
+	})
+	b.Run("ParallelRadixSortStrings", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ParallelRadixSortStrings(copySlice(data))
+		}
+	})
+}
@@ -0,0 +1,138 @@
+package qsort
+
+import (
+	"fmt"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// Select переставляет data так, что data[k] — элемент, который стоял бы на месте k
+// в отсортированном массиве, левее него нет больших, а правее — меньших
+// (nth_element в C++). Как и introSort, это быстрый выбор (quickselect) на partition
+// с медианой из трёх. Если два разбиения подряд не уменьшили отрезок хотя бы вдвое,
+// дальше опорный элемент выбирается медианой медиан (introselect Массера),
+// поэтому в худшем случае требуется O(n) сравнений.
+// Паникует, если k вне [0, len(data))
+func Select[T any](data []T, k int, comp comparator.Comparator[T]) {
+	if k < 0 || k >= len(data) {
+		panic(fmt.Errorf("qsort: Select index %d out of range [0, %d)", k, len(data)))
+	}
+	introSelect(data, k, comp, false)
+}
+
+// selectWindow — сколько разбиений quickselect должны уменьшить отрезок вдвое
+const selectWindow = 2
+
+// introSelect — Select; при linear опорный элемент сразу выбирается медианой медиан.
+// Каждые selectWindow разбиений отрезок должен уменьшиться вдвое, поэтому
+// до перехода на медиану медиан сделано не больше 2·selectWindow·n сравнений
+func introSelect[T any](data []T, k int, comp comparator.Comparator[T], linear bool) {
+	window, partitions := len(data), 0
+	for len(data) > insertionSortThreshold {
+		if !linear && partitions == selectWindow {
+			linear = len(data) > window/2
+			window, partitions = len(data), 0
+		}
+
+		var lo, hi int
+		if !linear {
+			partitions++
+			lo = partition(data, comp)
+			hi = lo + 1
+		} else {
+			// Трёхпутевое разбиение не даёт повторяющимся элементам
+			// свести на нет гарантию медианы медиан
+			lo, hi = partition3Way(data, comp, medianOfMedians[T])
+		}
+
+		switch {
+		case k < lo:
+			data = data[:lo]
+		case k >= hi:
+			data = data[hi:]
+			k -= hi
+		default:
+			return
+		}
+	}
+
+	insertionSort(data, comp)
+}
+
+// medianOfMedians выбирает опорный элемент, который больше не менее 30% элементов
+// и меньше не менее 30%: медианы пятёрок собираются в начале data, и среди них
+// рекурсивно выбирается медиана. Переставляет элементы data
+func medianOfMedians[T any](data []T, comp comparator.Comparator[T]) int {
+	if len(data) <= 5 {
+		insertionSort(data, comp)
+		return len(data) / 2
+	}
+
+	groups := 0
+	for i := 0; i+5 <= len(data); i += 5 {
+		insertionSort(data[i:i+5], comp)
+		data[groups], data[i+2] = data[i+2], data[groups]
+		groups++
+	}
+
+	introSelect(data[:groups], groups/2, comp, true)
+	return groups / 2
+}
+
+// PartialSort переставляет data так, что в начале по порядку стоят k наименьших
+// элементов; порядок остальных не определён. При k >= len(data) сортирует весь data.
+// Требует O(n + k log k) сравнений
+func PartialSort[T any](data []T, k int, comp comparator.Comparator[T]) {
+	if k <= 0 {
+		return
+	}
+	if k < len(data) {
+		Select(data, k-1, comp)
+	}
+	k = min(k, len(data))
+	introSort(data[:k], comp, maxDepth(k), insertionSortThreshold, lomutoPartition[T], nil)
+}
+
+// TopK возвращает k наименьших элементов data по порядку, не меняя data;
+// для k наибольших подходит comparator.Reverse. Каждая горутина проходит свой
+// блок data, храня k лучших элементов в куче, после чего кучи объединяются
+// и упорядочиваются PartialSort. Из opts важны WithMaxGoroutines и WithThreshold
+func TopK[T any](data []T, k int, comp comparator.Comparator[T], opts ...Option) []T {
+	k = min(k, len(data))
+	if k <= 0 {
+		return []T{}
+	}
+	c := newConfig(opts)
+
+	parts := chunks(len(data), c.maxGoroutines, max(c.threshold, k))
+	heaps := make([][]T, len(parts))
+	parallelFor(parts, func(i int, s span) {
+		heaps[i] = smallest(data[s.start:s.end], k, comp)
+	})
+
+	var merged []T
+	for _, h := range heaps {
+		merged = append(merged, h...)
+	}
+	PartialSort(merged, k, comp)
+	return merged[:k:k]
+}
+
+// smallest возвращает не более k наименьших элементов data в виде max-кучи
+func smallest[T any](data []T, k int, comp comparator.Comparator[T]) []T {
+	k = min(k, len(data))
+	h := make([]T, k)
+	copy(h, data)
+	for i := k/2 - 1; i >= 0; i-- {
+		siftDown(h, comp, i, k)
+	}
+
+	// В корне — наибольший из лучших: его вытесняет любой меньший элемент
+	for _, x := range data[k:] {
+		if comp.Compare(x, h[0]) < 0 {
+			h[0] = x
+			siftDown(h, comp, 0, k)
+		}
+	}
+	return h
+}
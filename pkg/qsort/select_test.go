@@ -0,0 +1,215 @@
+package qsort
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// checkSelected проверяет, что data — перестановка original с data[k] на своём
+// месте, и что левее k нет больших элементов, а правее — меньших
+func checkSelected(t *testing.T, data, original []int, k int) {
+	t.Helper()
+	expected := slices.Clone(original)
+	slices.Sort(expected)
+	if data[k] != expected[k] {
+		t.Fatalf("data[%d] = %d, want %d", k, data[k], expected[k])
+	}
+	for i, v := range data {
+		if i < k && v > data[k] || i > k && v < data[k] {
+			t.Fatalf("data[%d] = %d is on the wrong side of data[%d] = %d", i, v, k, data[k])
+		}
+	}
+	if !isPermutation(data, original) {
+		t.Fatal("data is not a permutation of the input")
+	}
+}
+
+// selectInputs — входы тестов выбора k-го элемента
+func selectInputs() []testInput {
+	return append([]testInput{
+		{"Single element", []int{42}},
+		{"Small", []int{5, 2, 8, 1, 9, 3}},
+	}, patternInputs(5000)...)
+}
+
+func TestSelect(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	for _, tt := range selectInputs() {
+		n := len(tt.data)
+		for _, k := range []int{0, n / 3, n / 2, n - 1} {
+			t.Run(fmt.Sprintf("%s/k=%d", tt.name, k), func(t *testing.T) {
+				data := copySlice(tt.data)
+				Select(data, k, comp)
+				checkSelected(t, data, tt.data, k)
+			})
+		}
+	}
+}
+
+func TestSelectAdversary(t *testing.T) {
+	// Число сравнений на элемент не растёт с n: после неудачных разбиений
+	// Select переходит на медиану медиан
+	for _, n := range []int{4096, 65536} {
+		for _, k := range []int{0, n / 2, n - 1} {
+			adversary := newAntiQuicksort(n)
+			indices := generateSortedInts(n)
+
+			Select(indices, k, adversary)
+
+			if limit := 16 * n; adversary.comparisons > limit {
+				t.Errorf("Select(n=%d, k=%d) made %d comparisons on adversarial input, want <= %d",
+					n, k, adversary.comparisons, limit)
+			}
+		}
+	}
+}
+
+func TestSelectMedianOfMedians(t *testing.T) {
+	// С linear каждый шаг выбирает опорный элемент медианой медиан
+	comp := comparator.OrderedC[int]{}
+
+	for _, tt := range selectInputs() {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, k := range []int{0, len(tt.data) / 2, len(tt.data) - 1} {
+				data := copySlice(tt.data)
+				introSelect(data, k, comp, true)
+				checkSelected(t, data, tt.data, k)
+			}
+		})
+	}
+}
+
+func TestSelectOutOfRange(t *testing.T) {
+	for _, k := range []int{-1, 3} {
+		t.Run(fmt.Sprint("k=", k), func(t *testing.T) {
+			defer func() {
+				err, ok := recover().(error)
+				if !ok || !strings.Contains(err.Error(), "out of range") {
+					t.Errorf("Recovered %v, want out of range error", err)
+				}
+			}()
+			Select([]int{1, 2, 3}, k, comparator.OrderedC[int]{})
+		})
+	}
+}
+
+func TestPartialSort(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	for _, tt := range selectInputs() {
+		n := len(tt.data)
+		for _, k := range []int{0, 1, n / 10, n, n + 5} {
+			t.Run(fmt.Sprintf("%s/k=%d", tt.name, k), func(t *testing.T) {
+				data := copySlice(tt.data)
+				expected := slices.Clone(tt.data)
+				slices.Sort(expected)
+
+				PartialSort(data, k, comp)
+
+				k := min(k, n)
+				if !slices.Equal(data[:k], expected[:k]) {
+					t.Errorf("PartialSort() prefix of %d elements is wrong", k)
+				}
+				if !isPermutation(data, tt.data) {
+					t.Error("data is not a permutation of the input")
+				}
+			})
+		}
+	}
+}
+
+func TestTopK(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	for _, tt := range selectInputs() {
+		n := len(tt.data)
+		for _, k := range []int{0, 1, 10, n, n + 5} {
+			for _, workers := range []int{1, 8} {
+				t.Run(fmt.Sprintf("%s/k=%d/workers=%d", tt.name, k, workers), func(t *testing.T) {
+					data := copySlice(tt.data)
+					expected := slices.Clone(tt.data)
+					slices.Sort(expected)
+
+					got := TopK(data, k, comp, WithMaxGoroutines(workers), WithThreshold(100))
+
+					if !slices.Equal(got, expected[:min(k, n)]) {
+						t.Errorf("TopK() = %v, want %v", got, expected[:min(k, n)])
+					}
+					if !slices.Equal(data, tt.data) {
+						t.Error("TopK changed data")
+					}
+				})
+			}
+		}
+	}
+}
+
+func TestTopKLargest(t *testing.T) {
+	data := []int{4, 9, 1, 7, 3, 9, 2}
+
+	got := TopK(data, 3, comparator.Reverse[int](comparator.OrderedC[int]{}))
+
+	if expected := []int{9, 9, 7}; !slices.Equal(got, expected) {
+		t.Errorf("TopK() = %v, want %v", got, expected)
+	}
+}
+
+func TestTopKPanic(t *testing.T) {
+	data := GenerateRandomInts(10000)
+	comp := comparator.ComparatorFunc[int](func(a, b int) int {
+		if a == 9999 || b == 9999 {
+			panic("boom")
+		}
+		return a - b
+	})
+	data[5000] = 9999
+
+	pe := recoverPanicError(t, func() {
+		TopK(data, 10, comp, WithMaxGoroutines(4), WithThreshold(100))
+	})
+	if pe.Value != "boom" {
+		t.Errorf("PanicError.Value = %v, want boom", pe.Value)
+	}
+}
+
+func BenchmarkSelect(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(1000000)
+
+	b.Run("Select", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			Select(copySlice(data), len(data)/2, comp)
+		}
+	})
+	b.Run("SequentialQuickSort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			SequentialQuickSort(copySlice(data), comp)
+		}
+	})
+}
+
+func BenchmarkTopK(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(1000000)
+
+	b.Run("TopK", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			TopK(data, 100, comp)
+		}
+	})
+	b.Run("PartialSort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			PartialSort(copySlice(data), 100, comp)
+		}
+	})
+	b.Run("ParallelQuickSort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			ParallelQuickSort(copySlice(data), comp)
+		}
+	})
+}
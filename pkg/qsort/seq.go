@@ -0,0 +1,131 @@
+package qsort
+
+import (
+	"iter"
+	"slices"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// Sorted собирает элементы seq в новый срез и сортирует его Sort с параметрами opts,
+// как slices.Sorted
+func Sorted[T any](seq iter.Seq[T], comp comparator.Comparator[T], opts ...Option) []T {
+	data := slices.Collect(seq)
+	Sort(data, comp, opts...)
+	return data
+}
+
+// SortedSeq возвращает последовательность элементов seq по порядку. Элементы
+// собираются и сортируются Sort при каждом проходе, когда запрошен первый из них
+func SortedSeq[T any](seq iter.Seq[T], comp comparator.Comparator[T], opts ...Option) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for _, v := range Sorted(seq, comp, opts...) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// LazySortedSeq — SortedSeq, который сортирует только то, что прочитано:
+// элементы собираются в срез и выдаются IncrementalSort. Если прочитаны
+// лишь первые k элементов, это стоит O(n + k log k) сравнений вместо O(n log n)
+func LazySortedSeq[T any](seq iter.Seq[T], comp comparator.Comparator[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		for v := range IncrementalSort(slices.Collect(seq), comp) {
+			if !yield(v) {
+				return
+			}
+		}
+	}
+}
+
+// IncrementalSort выдаёт элементы data по порядку, сортируя data на месте по мере чтения
+// (инкрементальная быстрая сортировка Паредеса и Наварро). Для очередного элемента
+// разбивается только начало массива до ближайшего уже поставленного опорного элемента,
+// и опорные элементы запоминаются в стеке. После чтения k элементов data[:k] —
+// k наименьших по порядку. Если разбиения раз за разом неудачны, отрезок
+// досортировывается пирамидальной сортировкой, как в introSort
+func IncrementalSort[T any](data []T, comp comparator.Comparator[T]) iter.Seq[T] {
+	return func(yield func(T) bool) {
+		// В стеке — опорные элементы по убыванию позиций; элементы левее каждого
+		// из них не больше него. depth — сколько разбиений привело к отрезку
+		// левее опорного элемента. Позиции до done уже на своих местах
+		type pivot struct{ pos, depth int }
+		stack := []pivot{{len(data), 0}}
+		depthLimit := maxDepth(len(data))
+		done := 0
+
+		for i := range data {
+			for i >= done {
+				top := &stack[len(stack)-1]
+				if top.pos == i {
+					stack = stack[:len(stack)-1]
+					done = i + 1
+					break
+				}
+
+				seg := data[i:top.pos]
+				switch {
+				case len(seg) <= insertionSortThreshold:
+					insertionSort(seg, comp)
+				case top.depth >= depthLimit:
+					heapSort(seg, comp)
+				default:
+					// Обе части отрезка оказываются на уровень глубже:
+					// левая — у нового опорного элемента, правая — у top
+					top.depth++
+					stack = append(stack, pivot{i + partition(seg, comp), top.depth})
+					continue
+				}
+				done = min(top.pos+1, len(data))
+				stack = stack[:len(stack)-1]
+			}
+
+			if !yield(data[i]) {
+				return
+			}
+		}
+	}
+}
+
+// SortedSlice — отсортированный срез с итераторами по нему
+type SortedSlice[T any] struct {
+	data []T
+	comp comparator.Comparator[T]
+}
+
+// NewSortedSlice сортирует data на месте Sort с параметрами opts и возвращает
+// SortedSlice над ним. Изменять data после этого нельзя
+func NewSortedSlice[T any](data []T, comp comparator.Comparator[T], opts ...Option) SortedSlice[T] {
+	Sort(data, comp, opts...)
+	return SortedSlice[T]{data, comp}
+}
+
+// Len возвращает число элементов
+func (s SortedSlice[T]) Len() int {
+	return len(s.data)
+}
+
+// All возвращает пары индекс–элемент по возрастанию, как slices.All
+func (s SortedSlice[T]) All() iter.Seq2[int, T] {
+	return slices.All(s.data)
+}
+
+// Backward возвращает пары индекс–элемент по убыванию, как slices.Backward
+func (s SortedSlice[T]) Backward() iter.Seq2[int, T] {
+	return slices.Backward(s.data)
+}
+
+// Values возвращает элементы по возрастанию
+func (s SortedSlice[T]) Values() iter.Seq[T] {
+	return slices.Values(s.data)
+}
+
+// Range возвращает по возрастанию элементы из полуинтервала [lo, hi).
+// Границы находятся двоичным поиском
+func (s SortedSlice[T]) Range(lo, hi T) iter.Seq[T] {
+	i := lowerBound(s.data, lo, s.comp)
+	j := max(i, lowerBound(s.data, hi, s.comp))
+	return slices.Values(s.data[i:j])
+}
@@ -0,0 +1,193 @@
+package qsort
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// seqInputs — входы тестов последовательностей
+func seqInputs() []testInput {
+	return append([]testInput{
+		{"Empty", []int{}},
+		{"Single element", []int{42}},
+		{"Small", []int{5, 2, 8, 1, 9, 3, 2}},
+	}, patternInputs(5000)...)
+}
+
+func TestSortedSeq(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	for _, tt := range seqInputs() {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := slices.Sorted(slices.Values(tt.data))
+
+			if got := Sorted(slices.Values(tt.data), comp, WithMaxGoroutines(4)); !slices.Equal(got, expected) {
+				t.Error("Sorted() result is wrong")
+			}
+			seqs := map[string]iter.Seq[int]{
+				"SortedSeq":     SortedSeq(slices.Values(tt.data), comp),
+				"LazySortedSeq": LazySortedSeq(slices.Values(tt.data), comp),
+			}
+			for name, seq := range seqs {
+				// Последовательность можно пройти несколько раз
+				for range 2 {
+					if got := slices.Collect(seq); !slices.Equal(got, expected) {
+						t.Errorf("%s result is wrong", name)
+					}
+				}
+			}
+		})
+	}
+
+	m := map[string]int{"b": 2, "c": 3, "a": 1}
+	if got := slices.Collect(SortedSeq(maps.Keys(m), comparator.StringC{})); !slices.Equal(got, []string{"a", "b", "c"}) {
+		t.Errorf("SortedSeq(maps.Keys) = %v", got)
+	}
+}
+
+func TestIncrementalSort(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	for _, tt := range seqInputs() {
+		t.Run(tt.name, func(t *testing.T) {
+			expected := slices.Sorted(slices.Values(tt.data))
+
+			for _, k := range []int{0, 1, 10, len(tt.data)} {
+				data := copySlice(tt.data)
+				var got []int
+				for v := range IncrementalSort(data, comp) {
+					if len(got) == k {
+						break
+					}
+					got = append(got, v)
+				}
+
+				k := min(k, len(tt.data))
+				if !slices.Equal(got, expected[:k]) || !slices.Equal(data[:k], expected[:k]) {
+					t.Errorf("first %d elements are wrong", k)
+				}
+				if !isPermutation(data, tt.data) {
+					t.Error("data is not a permutation of the input")
+				}
+			}
+		})
+	}
+}
+
+func TestIncrementalSortLazy(t *testing.T) {
+	// Первые элементы стоят O(n) сравнений, а не O(n log n), как полная сортировка
+	const n = 100000
+	data := GenerateRandomInts(n)
+	comp := &countingComparator{}
+
+	var got []int
+	for v := range IncrementalSort(data, comp) {
+		got = append(got, v)
+		if len(got) == 10 {
+			break
+		}
+	}
+
+	if limit := int64(5 * n); comp.comparisons.Load() > limit {
+		t.Errorf("IncrementalSort made %d comparisons for 10 elements, want <= %d", comp.comparisons.Load(), limit)
+	}
+	if !slices.IsSorted(got) {
+		t.Errorf("IncrementalSort yielded %v", got)
+	}
+}
+
+func TestSortedSlice(t *testing.T) {
+	data := []int{5, 1, 4, 1, 9, 2, 6}
+	s := NewSortedSlice(data, comparator.OrderedC[int]{})
+
+	if !slices.Equal(data, []int{1, 1, 2, 4, 5, 6, 9}) {
+		t.Fatalf("NewSortedSlice() sorted data to %v", data)
+	}
+	if s.Len() != len(data) {
+		t.Errorf("Len() = %d, want %d", s.Len(), len(data))
+	}
+
+	var indices, values []int
+	for i, v := range s.All() {
+		indices = append(indices, i)
+		values = append(values, v)
+	}
+	if !slices.Equal(indices, []int{0, 1, 2, 3, 4, 5, 6}) || !slices.Equal(values, data) {
+		t.Errorf("All() = %v, %v", indices, values)
+	}
+
+	indices, values = nil, nil
+	for i, v := range s.Backward() {
+		indices = append(indices, i)
+		values = append(values, v)
+	}
+	if !slices.Equal(indices, []int{6, 5, 4, 3, 2, 1, 0}) || !slices.Equal(values, []int{9, 6, 5, 4, 2, 1, 1}) {
+		t.Errorf("Backward() = %v, %v", indices, values)
+	}
+
+	if got := slices.Collect(s.Values()); !slices.Equal(got, data) {
+		t.Errorf("Values() = %v", got)
+	}
+
+	ranges := []struct {
+		lo, hi   int
+		expected []int
+	}{
+		{1, 5, []int{1, 1, 2, 4}},
+		{2, 3, []int{2}},
+		{3, 4, nil},
+		{0, 100, data},
+		{7, 3, nil},
+		{10, 20, nil},
+	}
+	for _, r := range ranges {
+		if got := slices.Collect(s.Range(r.lo, r.hi)); !slices.Equal(got, r.expected) {
+			t.Errorf("Range(%d, %d) = %v, want %v", r.lo, r.hi, got, r.expected)
+		}
+	}
+}
+
+func BenchmarkIncrementalSort(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	data := GenerateRandomInts(1000000)
+
+	b.Run("First100", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			n := 0
+			for range IncrementalSort(copySlice(data), comp) {
+				if n++; n == 100 {
+					break
+				}
+			}
+		}
+	})
+	b.Run("All", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			for range IncrementalSort(copySlice(data), comp) {
+			}
+		}
+	})
+	b.Run("SequentialQuickSort", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			SequentialQuickSort(copySlice(data), comp)
+		}
+	})
+}
+
+func TestIncrementalSortAdversary(t *testing.T) {
+	// Противник McIlroy подбирает значения так, чтобы каждое разбиение
+	// было неудачным; без перехода на heapSort сравнений было бы O(n²)
+	const n = 4096
+	adversary := newAntiQuicksort(n)
+
+	for range IncrementalSort(generateSortedInts(n), adversary) {
+	}
+
+	if limit := nLogN(n, 8); adversary.comparisons > limit {
+		t.Errorf("IncrementalSort made %d comparisons on adversarial input, want <= %d", adversary.comparisons, limit)
+	}
+}
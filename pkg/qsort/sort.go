@@ -1,13 +1,14 @@
 package qsort
 
 import (
+	"context"
 	"math/rand"
-	"runtime"
-	"sync"
 
 	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
 )
 
+// GenerateRandomInts генерирует size случайных чисел из [0, 10000) глобальным math/rand.
+// Воспроизводимые данные с другими распределениями выдаёт пакет generator
 func GenerateRandomInts(size int) []int {
 	data := make([]int, size)
 	for i := range data {
@@ -16,77 +17,141 @@ func GenerateRandomInts(size int) []int {
 	return data
 }
 
-// ParallelQuickSort - параллельная быстрая сортировка
+// parallelThreshold — массивы короче этого размера сортируются последовательно
+const parallelThreshold = 1000
+
+// ParallelQuickSort - параллельная быстрая сортировка.
+// Максимум горутин равен количеству ядер, чтобы не было простоя
 func ParallelQuickSort[T any](data []T, comp comparator.Comparator[T]) {
-	if len(data) <= 1 {
-		return
+	Sort(data, comp)
+}
+
+// scheme — движок сортировки для parallelQuickSort: разбиение, которым делятся
+// верхние уровни, и последовательная сортировка частей, доставшихся одной горутине.
+// Если задано parallelPartition, им разбиваются большие массивы.
+// Последовательная сортировка прекращает работу после закрытия канала cancel.
+// Если задано newTask, корневая задача строится им вместо quickSortTask:
+// так движок передаёт частям массива собственное состояние разбиения
+type scheme[T any] struct {
+	partition         partitionFunc[T]
+	parallelPartition func(data []T, comp comparator.Comparator[T], workers int) (lo, hi int)
+	sort              func(data []T, comp comparator.Comparator[T], cancel <-chan struct{})
+	newTask           func(data []T, comp comparator.Comparator[T], threshold int) task
+}
+
+// lomutoScheme — движок по умолчанию: разбиение Ломуто с опорным элементом,
+// выбранным choose, и introSort с порогом сортировки вставками cutoff
+func lomutoScheme[T any](choose pivotFunc[T], cutoff int) scheme[T] {
+	part := func(data []T, comp comparator.Comparator[T]) (int, int) {
+		pivotIndex := partitionWith(data, comp, choose)
+		return pivotIndex, pivotIndex + 1
+	}
+	return scheme[T]{
+		partition: part,
+		parallelPartition: func(data []T, comp comparator.Comparator[T], workers int) (int, int) {
+			pivotIndex := parallelPartition(data, comp, workers, choose)
+			return pivotIndex, pivotIndex + 1
+		},
+		sort: introSortWith(part, cutoff),
 	}
+}
 
-	maxGoroutines := runtime.NumCPU() // для оптимальности, максимум горутин берём как количество ядер, чтобы не было простоя
-	parallelQuickSort(data, comp, maxGoroutines)
+// introSortWith возвращает последовательную сортировку introSort с разбиением part
+func introSortWith[T any](part partitionFunc[T], cutoff int) func(data []T, comp comparator.Comparator[T], cancel <-chan struct{}) {
+	return func(data []T, comp comparator.Comparator[T], cancel <-chan struct{}) {
+		introSort(data, comp, maxDepth(len(data)), cutoff, part, cancel)
+	}
 }
 
-func parallelQuickSort[T any](data []T, comp comparator.Comparator[T], maxGoroutines int) {
-	if len(data) <= 1 {
-		return
+// parallelQuickSort сортирует data в пуле из maxGoroutines воркеров.
+// Подмассивы короче threshold сортируются последовательно.
+// После отмены ctx новые разбиения не начинаются, и возвращается ctx.Err()
+func parallelQuickSort[T any](ctx context.Context, data []T, comp comparator.Comparator[T], maxGoroutines, threshold int, s scheme[T]) error {
+	if err := ctx.Err(); err != nil || len(data) <= 1 {
+		return err
 	}
 
 	// Для небольших массивов используем последовательную сортировку
-	if len(data) < 1000 || maxGoroutines <= 1 {
-		SequentialQuickSort(data, comp)
-		return
+	if len(data) < threshold || maxGoroutines <= 1 {
+		s.sort(data, comp, ctx.Done())
+		return ctx.Err()
 	}
 
-	pivotIndex := partition(data, comp)
+	if s.newTask != nil {
+		runPool(ctx, maxGoroutines, s.newTask(data, comp, threshold))
+		return ctx.Err()
+	}
 
-	var wg sync.WaitGroup
+	depthLimit := maxDepth(len(data))
 
-	// Распределяем горутины между левой и правой частями
-	leftGoroutines := maxGoroutines / 2
-	rightGoroutines := maxGoroutines - leftGoroutines
+	runPool(ctx, maxGoroutines, func(w *worker) {
+		// Первое разбиение большого массива иначе было бы последовательным участком
+		// длиной O(n), ограничивающим ускорение по закону Амдала. Пока оно идёт,
+		// остальные воркеры всё равно простаивают
+		if s.parallelPartition != nil && len(data) >= parallelPartitionThreshold {
+			lo, hi := s.parallelPartition(data, comp, maxGoroutines)
+			w.spawn(quickSortTask(data[hi:], comp, threshold, depthLimit-1, s))
+			data = data[:lo]
+			depthLimit--
+		}
 
-	// Сортируем левую часть в отдельной горутине
-	if pivotIndex > 0 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			parallelQuickSort(data[:pivotIndex], comp, leftGoroutines)
-		}()
-	}
+		quickSortTask(data, comp, threshold, depthLimit, s)(w)
+	})
+	return ctx.Err()
+}
 
-	// Сортируем правую часть в отдельной горутине
-	if pivotIndex < len(data)-1 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			parallelQuickSort(data[pivotIndex+1:], comp, rightGoroutines)
-		}()
-	}
+// quickSortTask возвращает задачу сортировки data. Задача разбивает массив,
+// кладёт большую часть в очередь воркера, откуда её может украсть другой,
+// и продолжает с меньшей. Как и в introSort, число разбиений ограничено
+// depthLimit, после чего остаток сортируется последовательно
+func quickSortTask[T any](data []T, comp comparator.Comparator[T], threshold, depthLimit int, s scheme[T]) task {
+	return func(w *worker) {
+		for len(data) > 1 && len(data) >= threshold && depthLimit > 0 {
+			if w.canceled() {
+				return
+			}
+			depthLimit--
+
+			lo, hi := s.partition(data, comp)
+
+			larger, smaller := data[:lo], data[hi:]
+			if len(larger) < len(smaller) {
+				larger, smaller = smaller, larger
+			}
+			if len(larger) > 1 {
+				w.spawn(quickSortTask(larger, comp, threshold, depthLimit, s))
+			}
+			data = smaller
+		}
 
-	wg.Wait()
+		s.sort(data, comp, w.pool.cancel)
+	}
 }
 
-// SequentialQuickSort — последовательная быстрая сортировка для небольших массивов
+// SequentialQuickSort — последовательная быстрая сортировка для небольших массивов.
+// Глубина рекурсии ограничена (introsort), поэтому худший случай — O(n log n)
 func SequentialQuickSort[T any](data []T, comp comparator.Comparator[T]) {
 	if len(data) <= 1 {
 		return
 	}
 
-	pivotIndex := partition(data, comp)
-
-	SequentialQuickSort(data[:pivotIndex], comp)
-	SequentialQuickSort(data[pivotIndex+1:], comp)
+	introSort(data, comp, maxDepth(len(data)), insertionSortThreshold, lomutoPartition[T], nil)
 }
 
 // partition разбивает массив относительно опорного элемента
 // Возвращает индекс опорного элемента после разбиения
 func partition[T any](data []T, comp comparator.Comparator[T]) int {
+	// Используем медиану из трех для выбора опорного элемента
+	return partitionWith(data, comp, medianOfThree[T])
+}
+
+// partitionWith — partition с опорным элементом, выбранным choose
+func partitionWith[T any](data []T, comp comparator.Comparator[T], choose pivotFunc[T]) int {
 	if len(data) <= 1 {
 		return 0
 	}
 
-	// Используем медиану из трех для выбора опорного элемента
-	pivotIndex := medianOfThree(data, comp)
+	pivotIndex := choose(data, comp)
 
 	// Помещаем опорный элемент в конец массива
 	lastIndex := len(data) - 1
@@ -136,45 +201,5 @@ func medianOfThree[T any](data []T, comp comparator.Comparator[T]) int {
 
 // Альтернативная версия с настраиваемым порогом параллелизма
 func ParallelQuickSortWithThreshold[T any](data []T, comp comparator.Comparator[T], threshold int) {
-	if len(data) <= 1 {
-		return
-	}
-
-	maxGoroutines := runtime.NumCPU()
-	parallelQuickSortWithThreshold(data, comp, maxGoroutines, threshold)
-}
-
-func parallelQuickSortWithThreshold[T any](data []T, comp comparator.Comparator[T], maxGoroutines, threshold int) {
-	if len(data) <= 1 {
-		return
-	}
-
-	if len(data) < threshold || maxGoroutines <= 1 {
-		SequentialQuickSort(data, comp)
-		return
-	}
-
-	pivotIndex := partition(data, comp)
-
-	var wg sync.WaitGroup
-	leftGoroutines := maxGoroutines / 2
-	rightGoroutines := maxGoroutines - leftGoroutines
-
-	if pivotIndex > 0 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			parallelQuickSortWithThreshold(data[:pivotIndex], comp, leftGoroutines, threshold)
-		}()
-	}
-
-	if pivotIndex < len(data)-1 {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			parallelQuickSortWithThreshold(data[pivotIndex+1:], comp, rightGoroutines, threshold)
-		}()
-	}
-
-	wg.Wait()
+	Sort(data, comp, WithThreshold(threshold))
 }
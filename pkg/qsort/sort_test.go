@@ -1,36 +1,15 @@
 package qsort
 
 import (
+	"math"
 	"reflect"
 	"sort"
 	"testing"
 
 	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+	"github.com/leonid-voroshilov/mm-qsort/pkg/generator"
 )
 
-// Тестовые компараторы
-type IntComparator struct{}
-
-func (c IntComparator) Compare(a, b int) int {
-	if a < b {
-		return -1
-	} else if a > b {
-		return 1
-	}
-	return 0
-}
-
-type StringComparator struct{}
-
-func (c StringComparator) Compare(a, b string) int {
-	if a < b {
-		return -1
-	} else if a > b {
-		return 1
-	}
-	return 0
-}
-
 // Вспомогательные функции
 func isSorted[T any](data []T, comp comparator.Comparator[T]) bool {
 	for i := 1; i < len(data); i++ {
@@ -57,6 +36,33 @@ func generateReversedInts(size int) []int {
 	return data
 }
 
+// testInput — именованный вход табличных тестов
+type testInput struct {
+	name string
+	data []int
+}
+
+// patternInputs возвращает входы длины size с типичными распределениями:
+// на них по-разному ведут себя разбиения и выбор опорного элемента
+func patternInputs(size int) []testInput {
+	nearlySorted := generateSortedInts(size)
+	for i := 0; i+7 < size; i += max(size/10, 1) {
+		nearlySorted[i], nearlySorted[i+7] = nearlySorted[i+7], nearlySorted[i]
+	}
+
+	g := generator.New(1)
+	return []testInput{
+		{"Random", GenerateRandomInts(size)},
+		{"Sorted", generateSortedInts(size)},
+		{"Reversed", generateReversedInts(size)},
+		{"Nearly sorted", nearlySorted},
+		{"All equal", make([]int, size)},
+		{"Few unique", g.Ints(generator.FewUnique(4), size)},
+		{"Organ pipe", g.Ints(generator.OrganPipe(), size)},
+		{"Sawtooth", g.Ints(generator.Sawtooth(64), size)},
+	}
+}
+
 func copySlice[T any](src []T) []T {
 	dst := make([]T, len(src))
 	copy(dst, src)
@@ -65,7 +71,7 @@ func copySlice[T any](src []T) []T {
 
 // Тесты для ParallelQuickSort
 func TestParallelQuickSort(t *testing.T) {
-	comp := IntComparator{}
+	comp := comparator.OrderedC[int]{}
 
 	tests := []struct {
 		name string
@@ -102,7 +108,7 @@ func TestParallelQuickSort(t *testing.T) {
 }
 
 func TestParallelQuickSortLargeData(t *testing.T) {
-	comp := IntComparator{}
+	comp := comparator.OrderedC[int]{}
 	sizes := []int{1000, 5000, 10000}
 
 	for _, size := range sizes {
@@ -126,7 +132,7 @@ func TestParallelQuickSortLargeData(t *testing.T) {
 
 // Тесты для SequentialQuickSort
 func TestSequentialQuickSort(t *testing.T) {
-	comp := IntComparator{}
+	comp := comparator.OrderedC[int]{}
 
 	tests := []struct {
 		name string
@@ -160,7 +166,7 @@ func TestSequentialQuickSort(t *testing.T) {
 
 // Тесты для partition
 func TestPartition(t *testing.T) {
-	comp := IntComparator{}
+	comp := comparator.OrderedC[int]{}
 
 	tests := []struct {
 		name string
@@ -200,7 +206,7 @@ func TestPartition(t *testing.T) {
 }
 
 func TestPartitionEmptySlice(t *testing.T) {
-	comp := IntComparator{}
+	comp := comparator.OrderedC[int]{}
 	data := []int{}
 	result := partition(data, comp)
 
@@ -211,7 +217,7 @@ func TestPartitionEmptySlice(t *testing.T) {
 
 // Тесты для ParallelQuickSortWithThreshold
 func TestParallelQuickSortWithThreshold(t *testing.T) {
-	comp := IntComparator{}
+	comp := comparator.OrderedC[int]{}
 	data := GenerateRandomInts(2000)
 	expected := copySlice(data)
 	sort.Ints(expected)
@@ -245,7 +251,7 @@ func TestParallelQuickSortWithThreshold(t *testing.T) {
 
 // Тесты со строками
 func TestParallelQuickSortStrings(t *testing.T) {
-	comp := StringComparator{}
+	comp := comparator.OrderedC[string]{}
 	data := []string{"zebra", "apple", "banana", "cherry", "date"}
 	expected := []string{"apple", "banana", "cherry", "date", "zebra"}
 
@@ -260,9 +266,52 @@ func TestParallelQuickSortStrings(t *testing.T) {
 	}
 }
 
+func TestParallelQuickSortFloats(t *testing.T) {
+	comp := comparator.OrderedC[float64]{}
+	negZero := math.Copysign(0, -1)
+	data := []float64{3, math.Inf(1), 0, math.NaN(), -1, negZero, math.Inf(-1), math.NaN(), 0.5}
+
+	ParallelQuickSort(data, comp)
+
+	// NaN в начале, -0 перед +0
+	if !math.IsNaN(data[0]) || !math.IsNaN(data[1]) {
+		t.Errorf("NaNs are not at the beginning: %v", data)
+	}
+	if !math.Signbit(data[4]) || math.Signbit(data[5]) {
+		t.Errorf("-0 is not before +0: %v", data)
+	}
+	if !isSorted(data, comp) {
+		t.Errorf("Float result is not sorted: %v", data)
+	}
+}
+
+func TestParallelQuickSortCombinators(t *testing.T) {
+	// Указатели на записи: nil в конце, остальные по ключу по убыванию, затем по позиции
+	data := make([]*record, 20000)
+	for i, r := range generateRecords(len(data), 100) {
+		if i%10 != 0 {
+			data[i] = &r
+		}
+	}
+
+	byKey := comparator.By(func(r record) int { return r.key }, comparator.OrderedC[int]{})
+	comp := comparator.NullsLast(comparator.ThenBy(comparator.Reverse(byKey), func(r record) int { return r.seq }, comparator.OrderedC[int]{}))
+
+	Sort(data, comp, WithMaxGoroutines(8))
+
+	if !isSorted(data, comp) {
+		t.Error("Result is not sorted")
+	}
+	for i, r := range data {
+		if (r == nil) != (i >= len(data)-len(data)/10) {
+			t.Fatalf("nil pointers are not at the end: element %d is %v", i, r)
+		}
+	}
+}
+
 // Тесты на производительность
 func BenchmarkParallelQuickSort(b *testing.B) {
-	comp := IntComparator{}
+	comp := comparator.OrderedC[int]{}
 	sizes := []int{1000, 10000, 100000}
 
 	for _, size := range sizes {
@@ -299,7 +348,7 @@ func BenchmarkParallelQuickSort(b *testing.B) {
 }
 
 func BenchmarkSequentialQuickSort(b *testing.B) {
-	comp := IntComparator{}
+	comp := comparator.OrderedC[int]{}
 	data := GenerateRandomInts(10000)
 
 	b.ResetTimer()
@@ -313,7 +362,7 @@ func BenchmarkSequentialQuickSort(b *testing.B) {
 
 // Сравнительные бенчмарки
 func BenchmarkCompareParallelVsSequential(b *testing.B) {
-	comp := IntComparator{}
+	comp := comparator.OrderedC[int]{}
 	data := GenerateRandomInts(50000)
 
 	b.Run("Parallel", func(b *testing.B) {
@@ -335,9 +384,38 @@ func BenchmarkCompareParallelVsSequential(b *testing.B) {
 	})
 }
 
+// BenchmarkDistributions сравнивает схемы разбиения на входах разных распределений.
+// Генератор с фиксированным seed делает входы одинаковыми от запуска к запуску
+func BenchmarkDistributions(b *testing.B) {
+	const n = 100000
+	comp := comparator.OrderedC[int]{}
+	g := generator.New(1)
+
+	schemes := []struct {
+		name      string
+		partition PartitionScheme
+	}{
+		{"Lomuto", PartitionLomuto},
+		{"ThreeWay", PartitionThreeWay},
+		{"Pdq", PartitionPdq},
+		{"DualPivot", PartitionDualPivot},
+	}
+
+	for _, d := range generator.Distributions(n) {
+		data := g.Ints(d, n)
+		for _, s := range schemes {
+			b.Run(d.String()+"/"+s.name, func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					Sort(copySlice(data), comp, WithPartition(s.partition))
+				}
+			})
+		}
+	}
+}
+
 // Тесты на граничные случаи
 func TestEdgeCases(t *testing.T) {
-	comp := IntComparator{}
+	comp := comparator.OrderedC[int]{}
 
 	t.Run("Very large slice", func(t *testing.T) {
 		data := GenerateRandomInts(1000000)
@@ -374,7 +452,7 @@ func TestEdgeCases(t *testing.T) {
 
 // Тест на корректность работы с горутинами
 func TestConcurrencySafety(t *testing.T) {
-	comp := IntComparator{}
+	comp := comparator.OrderedC[int]{}
 
 	// Запускаем несколько сортировок параллельно
 	const numGoroutines = 10
@@ -0,0 +1,365 @@
+package qsort
+
+import (
+	"context"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// ScratchBuffer — стратегия выделения вспомогательной памяти для устойчивой сортировки
+type ScratchBuffer int
+
+const (
+	// ScratchFull — буфер на n элементов. Половины и результат слияния
+	// чередуются между массивом и буфером, слияния выполняются параллельно
+	ScratchFull ScratchBuffer = iota
+	// ScratchHalf — буфер на n/2 элементов. Перед слиянием в буфер
+	// копируется только левая половина, само слияние последовательное
+	ScratchHalf
+	// ScratchNone — без дополнительной памяти: слияние на месте
+	// с помощью поворотов (SymMerge), O(n log² n) сравнений и перестановок
+	ScratchNone
+)
+
+// mergeThreshold — слияния меньшего размера выполняются в одной горутине
+const mergeThreshold = 2048
+
+// StableSort — последовательная устойчивая сортировка слиянием:
+// равные элементы сохраняют исходный взаимный порядок
+func StableSort[T any](data []T, comp comparator.Comparator[T]) {
+	stableSort(data, comp, 1, ScratchFull)
+}
+
+// ParallelStableSort — параллельная устойчивая сортировка слиянием
+func ParallelStableSort[T any](data []T, comp comparator.Comparator[T]) {
+	Sort(data, comp, WithStable())
+}
+
+// ParallelStableSortWithScratch — параллельная устойчивая сортировка
+// с заданной стратегией вспомогательной памяти
+func ParallelStableSortWithScratch[T any](data []T, comp comparator.Comparator[T], scratch ScratchBuffer) {
+	Sort(data, comp, WithStable(), WithScratchBuffer(scratch))
+}
+
+func stableSort[T any](data []T, comp comparator.Comparator[T], maxGoroutines int, scratch ScratchBuffer) {
+	if len(data) <= 1 {
+		return
+	}
+
+	// Для небольших массивов используем последовательную сортировку
+	if len(data) < parallelThreshold || maxGoroutines <= 1 {
+		switch scratch {
+		case ScratchHalf:
+			mergeSortHalf(data, make([]T, (len(data)+1)/2), comp)
+		case ScratchNone:
+			mergeSortInPlace(data, comp)
+		default:
+			buf := make([]T, len(data))
+			copy(buf, data)
+			mergeSortFull(data, buf, comp)
+		}
+		return
+	}
+
+	runPool(context.Background(), maxGoroutines, func(w *worker) {
+		switch scratch {
+		case ScratchHalf:
+			parallelMergeSortHalf(w, data, make([]T, (len(data)+1)/2), comp, noop)
+		case ScratchNone:
+			parallelMergeSortInPlace(w, data, comp, noop)
+		default:
+			buf := make([]T, len(data))
+			copy(buf, data)
+			parallelMergeSortFull(w, data, buf, comp, noop)
+		}
+	})
+}
+
+// mergeSortFull сортирует data, используя buf того же размера. На входе buf
+// должен содержать те же элементы, что и data; на выходе его содержимое не определено
+func mergeSortFull[T any](data, buf []T, comp comparator.Comparator[T]) {
+	if len(data) <= insertionSortThreshold {
+		insertionSort(data, comp)
+		return
+	}
+
+	// Половины сортируем в buf, используя data как буфер, а затем сливаем обратно
+	mid := len(data) / 2
+	mergeSortFull(buf[:mid], data[:mid], comp)
+	mergeSortFull(buf[mid:], data[mid:], comp)
+	merge(buf[:mid], buf[mid:], data, comp)
+}
+
+// parallelMergeSortFull — версия mergeSortFull для пула воркеров.
+// Половины сортируются и сливаются параллельно; по завершении вызывается done
+func parallelMergeSortFull[T any](w *worker, data, buf []T, comp comparator.Comparator[T], done task) {
+	if len(data) < parallelThreshold {
+		mergeSortFull(data, buf, comp)
+		done(w)
+		return
+	}
+
+	mid := len(data) / 2
+	w.fork(
+		func(w *worker, done task) { parallelMergeSortFull(w, buf[:mid], data[:mid], comp, done) },
+		func(w *worker, done task) { parallelMergeSortFull(w, buf[mid:], data[mid:], comp, done) },
+		func(w *worker) { parallelMerge(w, buf[:mid], buf[mid:], data, comp, done) },
+	)
+}
+
+// parallelMerge устойчиво сливает отсортированные a и b в dst и вызывает done.
+// При равенстве первым идёт элемент из a. Большие слияния делятся пополам:
+// берётся средний элемент большего массива, и двоичным поиском находится
+// соответствующая ему граница в меньшем
+func parallelMerge[T any](w *worker, a, b, dst []T, comp comparator.Comparator[T], done task) {
+	if len(a)+len(b) < mergeThreshold {
+		merge(a, b, dst, comp)
+		done(w)
+		return
+	}
+
+	var i, j int
+	if len(a) >= len(b) {
+		// Элементы b, равные a[i], должны оказаться правее него
+		i = len(a) / 2
+		j = lowerBound(b, a[i], comp)
+	} else {
+		// Элементы a, равные b[j], должны оказаться левее него
+		j = len(b) / 2
+		i = upperBound(a, b[j], comp)
+	}
+
+	w.fork(
+		func(w *worker, done task) { parallelMerge(w, a[:i], b[:j], dst[:i+j], comp, done) },
+		func(w *worker, done task) { parallelMerge(w, a[i:], b[j:], dst[i+j:], comp, done) },
+		done,
+	)
+}
+
+// merge последовательно и устойчиво сливает a и b в dst
+func merge[T any](a, b, dst []T, comp comparator.Comparator[T]) {
+	i, j, k := 0, 0, 0
+	for i < len(a) && j < len(b) {
+		if comp.Compare(b[j], a[i]) < 0 {
+			dst[k] = b[j]
+			j++
+		} else {
+			dst[k] = a[i]
+			i++
+		}
+		k++
+	}
+	k += copy(dst[k:], a[i:])
+	copy(dst[k:], b[j:])
+}
+
+// lowerBound возвращает индекс первого элемента data, не меньшего value
+func lowerBound[T any](data []T, value T, comp comparator.Comparator[T]) int {
+	lo, hi := 0, len(data)
+	for lo < hi {
+		h := int(uint(lo+hi) >> 1)
+		if comp.Compare(data[h], value) < 0 {
+			lo = h + 1
+		} else {
+			hi = h
+		}
+	}
+	return lo
+}
+
+// upperBound возвращает индекс первого элемента data, строго большего value
+func upperBound[T any](data []T, value T, comp comparator.Comparator[T]) int {
+	lo, hi := 0, len(data)
+	for lo < hi {
+		h := int(uint(lo+hi) >> 1)
+		if comp.Compare(value, data[h]) >= 0 {
+			lo = h + 1
+		} else {
+			hi = h
+		}
+	}
+	return lo
+}
+
+// mergeSortHalf сортирует data, используя buf на ⌈n/2⌉ элементов
+func mergeSortHalf[T any](data, buf []T, comp comparator.Comparator[T]) {
+	if len(data) <= insertionSortThreshold {
+		insertionSort(data, comp)
+		return
+	}
+
+	mid := halfBufferMid(len(data))
+	mergeSortHalf(data[:mid], buf[:mid/2], comp)
+	mergeSortHalf(data[mid:], buf[mid/2:], comp)
+	mergeHalves(data, buf, mid, comp)
+}
+
+// parallelMergeSortHalf — версия mergeSortHalf для пула воркеров.
+// Половины сортируются параллельно, слияние последовательное
+func parallelMergeSortHalf[T any](w *worker, data, buf []T, comp comparator.Comparator[T], done task) {
+	if len(data) < parallelThreshold {
+		mergeSortHalf(data, buf, comp)
+		done(w)
+		return
+	}
+
+	mid := halfBufferMid(len(data))
+	w.fork(
+		func(w *worker, done task) { parallelMergeSortHalf(w, data[:mid], buf[:mid/2], comp, done) },
+		func(w *worker, done task) { parallelMergeSortHalf(w, data[mid:], buf[mid/2:], comp, done) },
+		func(w *worker) {
+			mergeHalves(data, buf, mid, comp)
+			done(w)
+		},
+	)
+}
+
+// halfBufferMid возвращает чётную середину массива длины n. Она позволяет поделить
+// буфер между половинами без пересечения: ⌈mid/2⌉ + ⌈(n-mid)/2⌉ = ⌈n/2⌉
+func halfBufferMid(n int) int {
+	return n / 2 &^ 1
+}
+
+// mergeHalves сливает отсортированные data[:mid] и data[mid:], используя buf.
+// Запись в data идёт не дальше текущей позиции чтения правой половины,
+// поэтому копировать в buf достаточно только левую
+func mergeHalves[T any](data, buf []T, mid int, comp comparator.Comparator[T]) {
+	if comp.Compare(data[mid], data[mid-1]) >= 0 {
+		// Половины уже стоят в нужном порядке
+		return
+	}
+
+	left := buf[:mid]
+	copy(left, data[:mid])
+	merge(left, data[mid:], data, comp)
+}
+
+// mergeSortInPlace сортирует data без дополнительной памяти
+func mergeSortInPlace[T any](data []T, comp comparator.Comparator[T]) {
+	if len(data) <= insertionSortThreshold {
+		insertionSort(data, comp)
+		return
+	}
+
+	mid := len(data) / 2
+	mergeSortInPlace(data[:mid], comp)
+	mergeSortInPlace(data[mid:], comp)
+	symMerge(data, 0, mid, len(data), comp)
+}
+
+// parallelMergeSortInPlace — версия mergeSortInPlace для пула воркеров
+func parallelMergeSortInPlace[T any](w *worker, data []T, comp comparator.Comparator[T], done task) {
+	if len(data) < parallelThreshold {
+		mergeSortInPlace(data, comp)
+		done(w)
+		return
+	}
+
+	mid := len(data) / 2
+	w.fork(
+		func(w *worker, done task) { parallelMergeSortInPlace(w, data[:mid], comp, done) },
+		func(w *worker, done task) { parallelMergeSortInPlace(w, data[mid:], comp, done) },
+		func(w *worker) { parallelSymMerge(w, data, 0, mid, len(data), comp, done) },
+	)
+}
+
+// symMerge устойчиво сливает отсортированные data[a:m] и data[m:b] на месте
+// (алгоритм SymMerge Кима и Куцнера, как в sort.Stable)
+func symMerge[T any](data []T, a, m, b int, comp comparator.Comparator[T]) {
+	// Одиночный элемент слева вставляем двоичным поиском
+	if m-a == 1 {
+		i := m + lowerBound(data[m:b], data[a], comp)
+		rotate(data[a:i], 1)
+		return
+	}
+
+	// Одиночный элемент справа вставляем двоичным поиском
+	if b-m == 1 {
+		i := a + upperBound(data[a:m], data[m], comp)
+		rotate(data[i:b], m-i)
+		return
+	}
+
+	start, mid, end := symMergeRotate(data, a, m, b, comp)
+	if a < start && start < mid {
+		symMerge(data, a, start, mid, comp)
+	}
+	if mid < end && end < b {
+		symMerge(data, mid, end, b, comp)
+	}
+}
+
+// parallelSymMerge — версия symMerge для пула воркеров. После поворота
+// две подзадачи не пересекаются и выполняются параллельно
+func parallelSymMerge[T any](w *worker, data []T, a, m, b int, comp comparator.Comparator[T], done task) {
+	if b-a < mergeThreshold || m-a == 1 || b-m == 1 {
+		symMerge(data, a, m, b, comp)
+		done(w)
+		return
+	}
+
+	start, mid, end := symMergeRotate(data, a, m, b, comp)
+	w.fork(
+		func(w *worker, done task) {
+			if a < start && start < mid {
+				parallelSymMerge(w, data, a, start, mid, comp, done)
+			} else {
+				done(w)
+			}
+		},
+		func(w *worker, done task) {
+			if mid < end && end < b {
+				parallelSymMerge(w, data, mid, end, b, comp, done)
+			} else {
+				done(w)
+			}
+		},
+		done,
+	)
+}
+
+// symMergeRotate выполняет шаг SymMerge: находит границы start и end, такие что
+// после поворота data[start:end] остаётся слить независимо data[a:mid] с границей
+// start и data[mid:b] с границей end
+func symMergeRotate[T any](data []T, a, m, b int, comp comparator.Comparator[T]) (start, mid, end int) {
+	mid = int(uint(a+b) >> 1)
+	n := mid + m
+	var r int
+	if m > mid {
+		start = n - b
+		r = mid
+	} else {
+		start = a
+		r = m
+	}
+	p := n - 1
+
+	for start < r {
+		c := int(uint(start+r) >> 1)
+		if comp.Compare(data[p-c], data[c]) >= 0 {
+			start = c + 1
+		} else {
+			r = c
+		}
+	}
+
+	end = n - start
+	if start < m && m < end {
+		rotate(data[start:end], m-start)
+	}
+
+	return start, mid, end
+}
+
+// rotate циклически сдвигает data влево на k позиций тремя разворотами
+func rotate[T any](data []T, k int) {
+	reverse(data[:k])
+	reverse(data[k:])
+	reverse(data)
+}
+
+func reverse[T any](data []T) {
+	for i, j := 0, len(data)-1; i < j; i, j = i+1, j-1 {
+		data[i], data[j] = data[j], data[i]
+	}
+}
@@ -0,0 +1,236 @@
+package qsort
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// record — запись с ключом сортировки и исходной позицией для проверки устойчивости
+type record struct {
+	key int
+	seq int
+}
+
+// recordComparator сравнивает записи только по ключу
+type recordComparator struct{}
+
+func (c recordComparator) Compare(a, b record) int {
+	return comparator.OrderedC[int]{}.Compare(a.key, b.key)
+}
+
+// generateRecords генерирует записи с ключами из [0, keys), так что ключи повторяются
+func generateRecords(size, keys int) []record {
+	data := make([]record, size)
+	for i := range data {
+		data[i] = record{key: rand.Intn(keys), seq: i}
+	}
+	return data
+}
+
+// stableSorted возвращает ожидаемый результат устойчивой сортировки
+func stableSorted(data []record) []record {
+	expected := copySlice(data)
+	sort.SliceStable(expected, func(i, j int) bool { return expected[i].key < expected[j].key })
+	return expected
+}
+
+var scratchBuffers = []struct {
+	name    string
+	scratch ScratchBuffer
+}{
+	{"Full", ScratchFull},
+	{"Half", ScratchHalf},
+	{"None", ScratchNone},
+}
+
+func TestStableSort(t *testing.T) {
+	comp := recordComparator{}
+
+	tests := []struct {
+		name string
+		data []record
+	}{
+		{"Empty slice", []record{}},
+		{"Single element", []record{{1, 0}}},
+		{"All equal keys", generateRecords(100, 1)},
+		{"Two keys", generateRecords(1000, 2)},
+		{"Many ties", generateRecords(5000, 50)},
+		{"Few ties", generateRecords(5000, 1000000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+
+			StableSort(data, comp)
+
+			if !reflect.DeepEqual(data, stableSorted(tt.data)) {
+				t.Error("StableSort() didn't preserve order of equal elements")
+			}
+		})
+	}
+}
+
+func TestParallelStableSort(t *testing.T) {
+	comp := recordComparator{}
+
+	for _, size := range []int{0, 1, 13, 1000, 5000, 100000} {
+		input := generateRecords(size, size/10+1)
+		expected := stableSorted(input)
+
+		t.Run("size_"+strconv.Itoa(size), func(t *testing.T) {
+			data := copySlice(input)
+			ParallelStableSort(data, comp)
+			if !reflect.DeepEqual(data, expected) {
+				t.Error("ParallelStableSort() didn't preserve order of equal elements")
+			}
+		})
+
+		for _, sb := range scratchBuffers {
+			t.Run("size_"+strconv.Itoa(size)+"_"+sb.name, func(t *testing.T) {
+				data := copySlice(input)
+				ParallelStableSortWithScratch(data, comp, sb.scratch)
+				if !reflect.DeepEqual(data, expected) {
+					t.Error("ParallelStableSortWithScratch() didn't preserve order of equal elements")
+				}
+
+				// Явно задаём бюджет горутин, чтобы проверить параллельные слияния на любой машине
+				data = copySlice(input)
+				stableSort(data, comp, 8, sb.scratch)
+				if !reflect.DeepEqual(data, expected) {
+					t.Error("stableSort() with 8 goroutines didn't preserve order of equal elements")
+				}
+			})
+		}
+	}
+}
+
+// person — запись с первичным и вторичным ключами
+type person struct {
+	name string
+	age  int
+}
+
+type byName struct{}
+
+func (byName) Compare(a, b person) int {
+	return comparator.OrderedC[string]{}.Compare(a.name, b.name)
+}
+
+type byAge struct{}
+
+func (byAge) Compare(a, b person) int {
+	return comparator.OrderedC[int]{}.Compare(a.age, b.age)
+}
+
+func TestStableSortAfterPrimarySort(t *testing.T) {
+	// Сортировка по вторичному ключу, затем устойчивая — по первичному
+	people := []person{
+		{"Eve", 30}, {"Bob", 25}, {"Alice", 30}, {"Dan", 25}, {"Carol", 35},
+	}
+	expected := []person{
+		{"Bob", 25}, {"Dan", 25}, {"Alice", 30}, {"Eve", 30}, {"Carol", 35},
+	}
+
+	ParallelQuickSort(people, byName{})
+	ParallelStableSort(people, byAge{})
+
+	if !reflect.DeepEqual(people, expected) {
+		t.Errorf("ParallelStableSort() = %v, want %v", people, expected)
+	}
+}
+
+func TestParallelMerge(t *testing.T) {
+	comp := recordComparator{}
+
+	// Все ключи совпадают: элементы a должны идти раньше элементов b
+	a := make([]record, 3000)
+	b := make([]record, 5000)
+	for i := range a {
+		a[i] = record{key: i / 1000, seq: i}
+	}
+	for i := range b {
+		b[i] = record{key: i / 2000, seq: len(a) + i}
+	}
+	dst := make([]record, len(a)+len(b))
+
+	runPool(context.Background(), 8, func(w *worker) {
+		parallelMerge(w, a, b, dst, comp, noop)
+	})
+
+	expected := stableSorted(append(copySlice(a), b...))
+	if !reflect.DeepEqual(dst, expected) {
+		t.Error("parallelMerge() didn't preserve order of equal elements")
+	}
+}
+
+func TestSymMerge(t *testing.T) {
+	comp := recordComparator{}
+
+	tests := []struct {
+		name        string
+		left, right int
+	}{
+		{"Single left", 1, 20},
+		{"Single right", 20, 1},
+		{"Balanced", 50, 50},
+		{"Unbalanced", 10, 90},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := generateRecords(tt.left+tt.right, 5)
+			sort.SliceStable(data[:tt.left], func(i, j int) bool { return data[i].key < data[j].key })
+			sort.SliceStable(data[tt.left:], func(i, j int) bool { return data[tt.left+i].key < data[tt.left+j].key })
+			expected := stableSorted(data)
+
+			symMerge(data, 0, tt.left, len(data), comp)
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Errorf("symMerge() = %v, want %v", data, expected)
+			}
+		})
+	}
+}
+
+func TestRotate(t *testing.T) {
+	data := []int{1, 2, 3, 4, 5, 6, 7}
+	expected := []int{4, 5, 6, 7, 1, 2, 3}
+
+	rotate(data, 3)
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("rotate() = %v, want %v", data, expected)
+	}
+}
+
+func BenchmarkStableSort(b *testing.B) {
+	comp := recordComparator{}
+	data := generateRecords(100000, 1000)
+
+	b.Run("Sequential", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			testData := copySlice(data)
+			b.StartTimer()
+			StableSort(testData, comp)
+			b.StopTimer()
+		}
+	})
+
+	for _, sb := range scratchBuffers {
+		b.Run("Parallel_"+sb.name, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(data)
+				b.StartTimer()
+				ParallelStableSortWithScratch(testData, comp, sb.scratch)
+				b.StopTimer()
+			}
+		})
+	}
+}
@@ -0,0 +1,63 @@
+package qsort
+
+import (
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+)
+
+// SequentialQuickSort3Way — последовательная быстрая сортировка с трёхпутевым разбиением.
+// Элементы, равные опорному, сразу встают на свои места и в рекурсию не попадают,
+// поэтому на входах с большим числом повторов она заметно быстрее SequentialQuickSort
+func SequentialQuickSort3Way[T any](data []T, comp comparator.Comparator[T]) {
+	if len(data) <= 1 {
+		return
+	}
+
+	introSort(data, comp, maxDepth(len(data)), insertionSortThreshold, threeWayPartition(medianOfThree[T]), nil)
+}
+
+// ParallelQuickSort3Way — параллельная быстрая сортировка с трёхпутевым разбиением
+func ParallelQuickSort3Way[T any](data []T, comp comparator.Comparator[T]) {
+	Sort(data, comp, WithPartition(PartitionThreeWay))
+}
+
+// threeWayScheme — движок с трёхпутевым разбиением для parallelQuickSort
+func threeWayScheme[T any](choose pivotFunc[T], cutoff int) scheme[T] {
+	part := threeWayPartition(choose)
+	return scheme[T]{partition: part, sort: introSortWith(part, cutoff)}
+}
+
+// threeWayPartition приводит partition3Way с опорным элементом, выбранным choose, к виду partitionFunc
+func threeWayPartition[T any](choose pivotFunc[T]) partitionFunc[T] {
+	return func(data []T, comp comparator.Comparator[T]) (int, int) {
+		return partition3Way(data, comp, choose)
+	}
+}
+
+// partition3Way разбивает массив на три части (задача о голландском флаге):
+// data[:lt] < pivot, data[lt:gt] == pivot, data[gt:] > pivot.
+// Возвращает границы lt и gt. Опорный элемент выбирает choose
+func partition3Way[T any](data []T, comp comparator.Comparator[T], choose pivotFunc[T]) (lt, gt int) {
+	if len(data) <= 1 {
+		return 0, len(data)
+	}
+
+	pivot := data[choose(data, comp)]
+
+	// Инвариант: data[:lt] < pivot, data[lt:i] == pivot, data[gt:] > pivot
+	lt, i, gt := 0, 0, len(data)
+	for i < gt {
+		switch c := comp.Compare(data[i], pivot); {
+		case c < 0:
+			data[lt], data[i] = data[i], data[lt]
+			lt++
+			i++
+		case c > 0:
+			gt--
+			data[i], data[gt] = data[gt], data[i]
+		default:
+			i++
+		}
+	}
+
+	return lt, gt
+}
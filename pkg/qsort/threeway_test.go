@@ -0,0 +1,175 @@
+package qsort
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"testing"
+
+	comparator "github.com/leonid-voroshilov/mm-qsort/pkg/Comparator"
+	"github.com/leonid-voroshilov/mm-qsort/pkg/generator"
+)
+
+func TestSequentialQuickSort3Way(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	tests := []struct {
+		name string
+		data []int
+	}{
+		{"Empty slice", []int{}},
+		{"Single element", []int{42}},
+		{"Two elements reversed", []int{2, 1}},
+		{"Small random", []int{3, 1, 4, 1, 5, 9, 2, 6, 5}},
+		{"Already sorted", []int{1, 2, 3, 4, 5}},
+		{"Reverse sorted", []int{5, 4, 3, 2, 1}},
+		{"Duplicates", []int{1, 1, 1, 1, 1}},
+		{"Few unique", generator.New(1).Ints(generator.FewUnique(3), 5000)},
+		{"Random", GenerateRandomInts(5000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+			expected := copySlice(tt.data)
+			sort.Ints(expected)
+
+			SequentialQuickSort3Way(data, comp)
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Errorf("SequentialQuickSort3Way() = %v, want %v", data, expected)
+			}
+		})
+	}
+}
+
+func TestParallelQuickSort3Way(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	tests := []struct {
+		name string
+		data []int
+	}{
+		{"Empty slice", []int{}},
+		{"Single element", []int{42}},
+		{"Small random", []int{3, 1, 4, 1, 5, 9, 2, 6, 5}},
+		{"All same", make([]int, 10000)},
+		{"Few unique", generator.New(1).Ints(generator.FewUnique(5), 100000)},
+		{"Random", GenerateRandomInts(100000)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+			expected := copySlice(tt.data)
+			sort.Ints(expected)
+
+			ParallelQuickSort3Way(data, comp)
+
+			if !reflect.DeepEqual(data, expected) {
+				t.Errorf("ParallelQuickSort3Way() result doesn't match expected (size %d)", len(data))
+			}
+		})
+	}
+}
+
+func TestParallelQuickSort3WayStrings(t *testing.T) {
+	comp := comparator.OrderedC[string]{}
+	data := []string{"pear", "apple", "pear", "fig", "apple", "pear"}
+	expected := []string{"apple", "apple", "fig", "pear", "pear", "pear"}
+
+	ParallelQuickSort3Way(data, comp)
+
+	if !reflect.DeepEqual(data, expected) {
+		t.Errorf("ParallelQuickSort3Way(strings) = %v, want %v", data, expected)
+	}
+}
+
+func TestPartition3Way(t *testing.T) {
+	comp := comparator.OrderedC[int]{}
+
+	tests := []struct {
+		name string
+		data []int
+	}{
+		{"Single element", []int{42}},
+		{"Two elements", []int{2, 1}},
+		{"All equal", []int{7, 7, 7, 7}},
+		{"Multiple elements", []int{3, 1, 4, 1, 5, 9, 2, 6, 3, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := copySlice(tt.data)
+
+			lt, gt := partition3Way(data, comp, medianOfThree[int])
+			if lt >= gt {
+				t.Fatalf("partition3Way() returned empty equal range [%d, %d)", lt, gt)
+			}
+			pivot := data[lt]
+
+			for i := 0; i < lt; i++ {
+				if comp.Compare(data[i], pivot) >= 0 {
+					t.Errorf("Element %v at index %d should be less than pivot %v", data[i], i, pivot)
+				}
+			}
+			for i := lt; i < gt; i++ {
+				if comp.Compare(data[i], pivot) != 0 {
+					t.Errorf("Element %v at index %d should be equal to pivot %v", data[i], i, pivot)
+				}
+			}
+			for i := gt; i < len(data); i++ {
+				if comp.Compare(data[i], pivot) <= 0 {
+					t.Errorf("Element %v at index %d should be greater than pivot %v", data[i], i, pivot)
+				}
+			}
+		})
+	}
+}
+
+// Бенчмарки на входах с малым числом различных значений
+func BenchmarkFewUnique(b *testing.B) {
+	comp := comparator.OrderedC[int]{}
+	const size = 1000000
+
+	for _, unique := range []int{2, 10, 100, 10000} {
+		data := generator.New(1).Ints(generator.FewUnique(unique), size)
+		suffix := "_unique_" + strconv.Itoa(unique)
+
+		b.Run("Sequential"+suffix, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(data)
+				b.StartTimer()
+				SequentialQuickSort(testData, comp)
+				b.StopTimer()
+			}
+		})
+
+		b.Run("Sequential3Way"+suffix, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(data)
+				b.StartTimer()
+				SequentialQuickSort3Way(testData, comp)
+				b.StopTimer()
+			}
+		})
+
+		b.Run("Parallel"+suffix, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(data)
+				b.StartTimer()
+				ParallelQuickSort(testData, comp)
+				b.StopTimer()
+			}
+		})
+
+		b.Run("Parallel3Way"+suffix, func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				testData := copySlice(data)
+				b.StartTimer()
+				ParallelQuickSort3Way(testData, comp)
+				b.StopTimer()
+			}
+		})
+	}
+}